@@ -2,7 +2,9 @@ package chunked
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/kstremick/mango/core/mangoerr"
 	"github.com/kstremick/mango/core/primitive"
 
 	"github.com/apache/arrow/go/v12/arrow"
@@ -43,7 +45,7 @@ import (
 // Returning the validity array
 func StringChunk(s arrow.Array) ([]string, []bool, error) {
 	if !arrow.TypeEqual(s.DataType(), arrow.BinaryTypes.String) {
-		return nil, nil, fmt.Errorf("series is not of type string")
+		return nil, nil, &mangoerr.TypeMismatchError{Expected: "string", Actual: s.DataType()}
 	}
 	ret := make([]string, s.Len())
 	valids := make([]bool, s.Len())
@@ -61,8 +63,19 @@ func StringChunk(s arrow.Array) ([]string, []bool, error) {
 // ExtractVaueFn returns a function that extracts the value at index i.
 func ExtractValueFnT[T primitive.Primitive](s arrow.Array) (func(int) T, error) {
 	desiredType := primitive.ToArrowDatatypeT[T]()
-	if !arrow.TypeEqual(s.DataType(), desiredType, nil) {
-		return nil, fmt.Errorf("series is not of type %T", primitive.ToArrowDatatypeT[T]())
+	if !arrow.TypeEqual(s.DataType(), desiredType) {
+		return nil, &mangoerr.TypeMismatchError{Expected: primitive.ToArrowDatatypeT[T]().String(), Actual: s.DataType()}
+	}
+	if _, err := primitive.ToArrowDatatype(*new(T)); err != nil {
+		// T is a named type like `type ID int64`, whose values are converted from the underlying type.
+		fn, err := ExtractValueFn(s)
+		if err != nil {
+			return nil, err
+		}
+		t := reflect.TypeOf(*new(T))
+		return func(i int) T {
+			return reflect.ValueOf(fn(i)).Convert(t).Interface().(T)
+		}, nil
 	}
 	switch s.DataType().ID() {
 	case arrow.STRING:
@@ -82,7 +95,7 @@ func ExtractValueFnT[T primitive.Primitive](s arrow.Array) (func(int) T, error)
 			return any(s.(*array.Int64).Value(i)).(T)
 		}, nil
 	}
-	return nil, fmt.Errorf("series is not of type %T", primitive.ToArrowDatatypeT[T]())
+	return nil, &mangoerr.TypeMismatchError{Expected: primitive.ToArrowDatatypeT[T]().String(), Actual: s.DataType()}
 }
 
 // ExtractValueFn returns a function that extracts the value at index i.
@@ -104,8 +117,60 @@ func ExtractValueFn(s arrow.Array) (func(int) interface{}, error) {
 		return func(i int) interface{} {
 			return s.(*array.Int64).Value(i)
 		}, nil
+	case arrow.TIMESTAMP:
+		unit := s.DataType().(*arrow.TimestampType).Unit
+		return func(i int) interface{} {
+			return s.(*array.Timestamp).Value(i).ToTime(unit)
+		}, nil
+	case arrow.DATE32:
+		return func(i int) interface{} {
+			return s.(*array.Date32).Value(i).ToTime()
+		}, nil
+	case arrow.STRUCT:
+		// Structs are extracted as maps from field name to value, where null fields are nil.
+		st := s.(*array.Struct)
+		fields := s.DataType().(*arrow.StructType).Fields()
+		fieldFns := make([]func(int) interface{}, len(fields))
+		for f := range fields {
+			fn, err := ExtractValueFn(st.Field(f))
+			if err != nil {
+				return nil, err
+			}
+			fieldFns[f] = fn
+		}
+		return func(i int) interface{} {
+			ret := make(map[string]interface{}, len(fields))
+			for f, field := range fields {
+				if st.Field(f).IsValid(i) {
+					ret[field.Name] = fieldFns[f](i)
+				} else {
+					ret[field.Name] = nil
+				}
+			}
+			return ret
+		}, nil
+	case arrow.LIST:
+		// Lists are extracted as slices, where null elements are nil.
+		list := s.(*array.List)
+		elems := list.ListValues()
+		elemFn, err := ExtractValueFn(elems)
+		if err != nil {
+			return nil, err
+		}
+		return func(i int) interface{} {
+			start, end := list.ValueOffsets(i)
+			ret := make([]interface{}, 0, end-start)
+			for j := int(start); j < int(end); j++ {
+				if elems.IsValid(j) {
+					ret = append(ret, elemFn(j))
+				} else {
+					ret = append(ret, nil)
+				}
+			}
+			return ret
+		}, nil
 	}
-	return nil, fmt.Errorf("unknown series type %T", s.DataType())
+	return nil, fmt.Errorf("%w: unknown series type %s", mangoerr.ErrTypeMismatch, s.DataType())
 }
 
 // ExtractChunk converts the chunk to an array of type T.
@@ -0,0 +1,106 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// ToArrowTable returns the DataFrame as an arrow Table.
+// The table shares the chunks of every column, so this operation does not copy the data.
+// The caller should Release the table when done with it.
+func (df *DataFrame) ToArrowTable() (arrow.Table, error) {
+	if err := df.Validate(); err != nil {
+		return nil, err
+	}
+	schema := df.Schema()
+	cols := make([]arrow.Column, len(df.Series))
+	for i, s := range df.Series {
+		chunked := arrow.NewChunked(s.DataType(), s.Chunks())
+		cols[i] = *arrow.NewColumn(schema.Field(i), chunked)
+		chunked.Release()
+	}
+	tbl := array.NewTable(schema, cols, int64(df.Height()))
+	for i := range cols {
+		cols[i].Release()
+	}
+	return tbl, nil
+}
+
+// ToRecordBatches returns the DataFrame as a slice of arrow Records.
+// A new record starts wherever any column starts a new chunk,
+// so records are zero-copy slices of the chunks of every column.
+// The caller should Release every record when done with them.
+func (df *DataFrame) ToRecordBatches() ([]arrow.Record, error) {
+	tbl, err := df.ToArrowTable()
+	if err != nil {
+		return nil, err
+	}
+	defer tbl.Release()
+
+	reader := array.NewTableReader(tbl, -1)
+	defer reader.Release()
+	var recs []arrow.Record
+	for reader.Next() {
+		rec := reader.Record()
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// FromArrowTable creates a new DataFrame from an arrow Table, without copying the data.
+func FromArrowTable(tbl arrow.Table) (*DataFrame, error) {
+	cols := make([]series.Series, tbl.NumCols())
+	for i := range cols {
+		col := tbl.Column(i)
+		chunked := col.Data()
+		chunked.Retain()
+		cols[i] = series.NewSeriesFromChunked(col.Name(), chunked)
+	}
+	return newDataFrameOrRelease(cols)
+}
+
+// FromRecord creates a new DataFrame from an arrow Record, without copying the data.
+func FromRecord(rec arrow.Record) (*DataFrame, error) {
+	return FromRecords(rec.Schema(), []arrow.Record{rec})
+}
+
+// FromRecords creates a new DataFrame from arrow Records sharing the same schema.
+// Every record becomes a chunk of the columns, so this operation does not copy the data.
+func FromRecords(schema *arrow.Schema, recs []arrow.Record) (*DataFrame, error) {
+	for j, rec := range recs {
+		if !rec.Schema().Equal(schema) {
+			return nil, fmt.Errorf("record %d has schema %s, expected %s", j, rec.Schema(), schema)
+		}
+	}
+	cols := make([]series.Series, len(schema.Fields()))
+	for i, field := range schema.Fields() {
+		chunks := make([]arrow.Array, len(recs))
+		for j, rec := range recs {
+			chunks[j] = rec.Column(i)
+		}
+		cols[i] = series.NewSeriesFromChunked(field.Name, arrow.NewChunked(field.Type, chunks))
+	}
+	return newDataFrameOrRelease(cols)
+}
+
+// newDataFrameOrRelease is like NewDataFrameChecked, but releases the columns when they do not form a valid DataFrame.
+func newDataFrameOrRelease(cols []series.Series) (*DataFrame, error) {
+	df, err := NewDataFrameChecked(cols)
+	if err != nil {
+		releaseColumns(cols)
+		return nil, err
+	}
+	return df, nil
+}
+
+// releaseColumns releases the columns built before an error. Zero Series are ignored.
+func releaseColumns(cols []series.Series) {
+	for i := range cols {
+		cols[i].Release()
+	}
+}
@@ -0,0 +1,117 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/zeebo/assert"
+)
+
+func TestToArrowTable(t *testing.T) {
+	a := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2, 3}),
+		series.NewSeries("y", []string{"a", "b", "c"}),
+	})
+	b := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{4}),
+		series.NewSeries("y", []string{"d"}),
+	})
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatVertical)
+	assert.NoError(t, err)
+
+	tbl, err := df.ToArrowTable()
+	assert.NoError(t, err)
+	defer tbl.Release()
+	assert.Equal(t, int64(4), tbl.NumRows())
+	assert.Equal(t, int64(2), tbl.NumCols())
+	assert.Equal(t, "y", tbl.Column(1).Name())
+	x, _ := df.Column("x")
+	assert.Equal(t, x.Chunks()[0], tbl.Column(0).Data().Chunk(0))
+
+	roundTrip, err := dataframe.FromArrowTable(tbl)
+	assert.NoError(t, err)
+	assert.Equal(t, df.String(), roundTrip.String())
+
+	invalid := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2}),
+		series.NewSeries("y", []int64{1}),
+	})
+	_, err = invalid.ToArrowTable()
+	assert.Error(t, err)
+}
+
+func TestRecordBatches(t *testing.T) {
+	a := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2, 3}),
+		series.NewSeries("y", []float64{1, 2, 3}),
+	})
+	b := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{4}),
+		series.NewSeries("y", []float64{4}),
+	})
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatVertical)
+	assert.NoError(t, err)
+
+	recs, err := df.ToRecordBatches()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(recs))
+	assert.Equal(t, int64(3), recs[0].NumRows())
+	assert.Equal(t, int64(1), recs[1].NumRows())
+
+	roundTrip, err := dataframe.FromRecords(recs[0].Schema(), recs)
+	assert.NoError(t, err)
+	assert.Equal(t, df.String(), roundTrip.String())
+	for _, rec := range recs {
+		rec.Release()
+	}
+}
+
+func TestFromRecord(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "ok", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	}, nil)
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	b.Field(1).(*array.BooleanBuilder).AppendValues([]bool{true, false}, []bool{true, false})
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	df, err := dataframe.FromRecord(rec)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "ok"}, df.GetColumnNames())
+	ok, _ := df.Column("ok")
+	assert.DeepEqual(t, []interface{}{true, nil}, values(ok))
+
+	other := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	_, err = dataframe.FromRecords(other, []arrow.Record{rec})
+	assert.Error(t, err)
+}
+
+func TestFromArrowTableInvalid(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	b.Field(1).(*array.Int64Builder).AppendValues([]int64{3, 4}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+	tbl := array.NewTableFromRecords(schema, []arrow.Record{rec})
+	defer tbl.Release()
+
+	_, err := dataframe.FromArrowTable(tbl)
+	assert.Error(t, err)
+	_, err = dataframe.FromRecord(rec)
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package dataframe
+
+import (
+	"fmt"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// ConcatHow is the way DataFrames are combined by Concat.
+type ConcatHow int
+
+const (
+	// ConcatVertical appends the rows of the DataFrames, which must have the same columns.
+	ConcatVertical ConcatHow = iota
+	// ConcatHorizontal appends the columns of the DataFrames, which must have the same height.
+	ConcatHorizontal
+	// ConcatDiagonal appends the rows of the DataFrames, taking the union of their columns.
+	// Columns missing from a DataFrame are filled with nulls.
+	ConcatDiagonal
+)
+
+// ConcatRechunkThreshold is the number of chunks above which the columns resulting from
+// a vertical or diagonal Concat are rechunked into a single chunk, see series.Series.Rechunk.
+// Many small chunks make reading values and compute functions slower.
+// Set it to zero or less to never rechunk.
+var ConcatRechunkThreshold = 64
+
+// Concat combines multiple DataFrames into one.
+// Vertical and diagonal concatenation append the chunks of each column, so they do not copy the data,
+// unless a column ends up with more than ConcatRechunkThreshold chunks.
+func Concat(frames []*DataFrame, how ConcatHow) (*DataFrame, error) {
+	if len(frames) == 0 {
+		return NewDataFrame(nil), nil
+	}
+	var df *DataFrame
+	var err error
+	switch how {
+	case ConcatVertical:
+		df, err = concatVertical(frames)
+	case ConcatHorizontal:
+		return concatHorizontal(frames)
+	case ConcatDiagonal:
+		df, err = concatDiagonal(frames)
+	default:
+		return nil, fmt.Errorf("unknown concat method %d", how)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ConcatRechunkThreshold > 0 {
+		for i := range df.Series {
+			if df.Series[i].NumChunks() <= ConcatRechunkThreshold {
+				continue
+			}
+			rechunked, err := df.Series[i].Rechunked()
+			if err != nil {
+				df.Release()
+				return nil, err
+			}
+			df.Series[i].Release()
+			df.Series[i] = rechunked
+		}
+	}
+	return df, nil
+}
+
+func concatVertical(frames []*DataFrame) (*DataFrame, error) {
+	names := frames[0].GetColumnNames()
+	for _, df := range frames[1:] {
+		if len(df.Series) != len(names) {
+			return nil, &mangoerr.ShapeMismatchError{What: "number of columns", Expected: len(names), Actual: len(df.Series)}
+		}
+	}
+	out := make([]series.Series, len(names))
+	for i, name := range names {
+		dtype := frames[0].Series[i].DataType()
+		var chunks []arrow.Array
+		for _, df := range frames {
+			col, err := df.Column(name)
+			if err != nil {
+				releaseColumns(out[:i])
+				return nil, err
+			}
+			if !arrow.TypeEqual(dtype, col.DataType()) {
+				releaseColumns(out[:i])
+				return nil, fmt.Errorf("column %s: cannot append series: %w", name, &mangoerr.TypeMismatchError{Expected: dtype.String(), Actual: col.DataType()})
+			}
+			chunks = append(chunks, col.Chunks()...)
+		}
+		out[i] = series.NewSeriesFromChunked(name, arrow.NewChunked(dtype, chunks))
+	}
+	return NewDataFrame(out), nil
+}
+
+func concatHorizontal(frames []*DataFrame) (*DataFrame, error) {
+	height := frames[0].Height()
+	seen := make(map[string]bool)
+	var out []series.Series
+	for _, df := range frames {
+		if df.Height() != height {
+			releaseColumns(out)
+			return nil, &mangoerr.ShapeMismatchError{What: "height", Expected: height, Actual: df.Height()}
+		}
+		for _, col := range df.Series {
+			if seen[col.Name] {
+				releaseColumns(out)
+				return nil, fmt.Errorf("duplicate column: %s", col.Name)
+			}
+			seen[col.Name] = true
+			col.Retain()
+			out = append(out, col)
+		}
+	}
+	return NewDataFrame(out), nil
+}
+
+func concatDiagonal(frames []*DataFrame) (*DataFrame, error) {
+	var names []string
+	dtypes := make(map[string]arrow.DataType)
+	for _, df := range frames {
+		for _, col := range df.Series {
+			dtype, ok := dtypes[col.Name]
+			if !ok {
+				names = append(names, col.Name)
+				dtypes[col.Name] = col.DataType()
+			} else if !arrow.TypeEqual(dtype, col.DataType()) {
+				return nil, fmt.Errorf("column %s: %w", col.Name, &mangoerr.TypeMismatchError{Expected: dtype.String(), Actual: col.DataType()})
+			}
+		}
+	}
+
+	mem := series.DefaultAllocator()
+	out := make([]series.Series, len(names))
+	for i, name := range names {
+		var chunks []arrow.Array
+		for _, df := range frames {
+			col, err := df.Column(name)
+			if err != nil {
+				nulls := array.MakeArrayOfNull(mem, dtypes[name], df.Height())
+				defer nulls.Release()
+				chunks = append(chunks, nulls)
+				continue
+			}
+			chunks = append(chunks, col.Chunks()...)
+		}
+		out[i] = series.NewSeriesFromChunked(name, arrow.NewChunked(dtypes[name], chunks))
+	}
+	return NewDataFrame(out), nil
+}
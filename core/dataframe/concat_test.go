@@ -0,0 +1,116 @@
+package dataframe_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestConcatVertical(t *testing.T) {
+	a := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2}),
+		series.NewSeries("y", []string{"a", "b"}),
+	})
+	b := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("y", []string{"c"}),
+		series.NewSeries("x", []int64{3}),
+	})
+
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatVertical)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"x", "y"}, df.GetColumnNames())
+	assert.Equal(t, 3, df.Height())
+	x, _ := df.Column("x")
+	assert.Equal(t, 2, x.NumChunks())
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(3)}, values(x))
+	y, _ := df.Column("y")
+	assert.DeepEqual(t, []interface{}{"a", "b", "c"}, values(y))
+
+	c := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []float64{1}),
+		series.NewSeries("y", []string{"c"}),
+	})
+	_, err = dataframe.Concat([]*dataframe.DataFrame{a, c}, dataframe.ConcatVertical)
+	assert.Error(t, err)
+
+	d := dataframe.NewDataFrame([]series.Series{series.NewSeries("x", []int64{1})})
+	_, err = dataframe.Concat([]*dataframe.DataFrame{a, d}, dataframe.ConcatVertical)
+	assert.Error(t, err)
+}
+
+func TestConcatHorizontal(t *testing.T) {
+	a := dataframe.NewDataFrame([]series.Series{series.NewSeries("x", []int64{1, 2})})
+	b := dataframe.NewDataFrame([]series.Series{series.NewSeries("y", []string{"a", "b"})})
+
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatHorizontal)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"x", "y"}, df.GetColumnNames())
+
+	_, err = dataframe.Concat([]*dataframe.DataFrame{a, a}, dataframe.ConcatHorizontal)
+	assert.Error(t, err)
+
+	c := dataframe.NewDataFrame([]series.Series{series.NewSeries("z", []int64{1})})
+	_, err = dataframe.Concat([]*dataframe.DataFrame{a, c}, dataframe.ConcatHorizontal)
+	assert.Error(t, err)
+}
+
+func TestConcatDiagonal(t *testing.T) {
+	a := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2}),
+		series.NewSeries("y", []string{"a", "b"}),
+	})
+	b := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("z", []float64{1.5}),
+		series.NewSeries("x", []int64{3}),
+	})
+
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatDiagonal)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"x", "y", "z"}, df.GetColumnNames())
+	assert.Equal(t, 3, df.Height())
+	y, _ := df.Column("y")
+	assert.DeepEqual(t, []interface{}{"a", "b", nil}, values(y))
+	z, _ := df.Column("z")
+	assert.DeepEqual(t, []interface{}{nil, nil, 1.5}, values(z))
+
+	c := dataframe.NewDataFrame([]series.Series{series.NewSeries("x", []string{"a"})})
+	_, err = dataframe.Concat([]*dataframe.DataFrame{a, c}, dataframe.ConcatDiagonal)
+	assert.Error(t, err)
+}
+
+func TestConcatRechunk(t *testing.T) {
+	defer func(threshold int) { dataframe.ConcatRechunkThreshold = threshold }(dataframe.ConcatRechunkThreshold)
+	frame := dataframe.NewDataFrame([]series.Series{series.NewSeries("x", []int64{1, 2})})
+
+	type testCase struct {
+		threshold int
+		frames    int
+		chunks    int
+	}
+	testCases := []testCase{
+		{threshold: 3, frames: 3, chunks: 3},
+		{threshold: 3, frames: 4, chunks: 1},
+		{threshold: 0, frames: 4, chunks: 4},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d frames, threshold %d", tc.frames, tc.threshold), func(t *testing.T) {
+			dataframe.ConcatRechunkThreshold = tc.threshold
+			frames := make([]*dataframe.DataFrame, tc.frames)
+			for i := range frames {
+				frames[i] = frame
+			}
+			df, err := dataframe.Concat(frames, dataframe.ConcatVertical)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.chunks, df.Series[0].NumChunks())
+			assert.Equal(t, 2*tc.frames, df.Height())
+			assert.Equal(t, int64(2), df.Series[0].ValueExn(2*tc.frames-1).Value)
+
+			assert.NoError(t, df.Rechunk())
+			assert.Equal(t, 1, df.Series[0].NumChunks())
+		})
+	}
+}
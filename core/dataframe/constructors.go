@@ -0,0 +1,157 @@
+package dataframe
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+// FromMap creates a new DataFrame from a map of column names to their values,
+// like pl.DataFrame({'a': [1, 2, 3]}) in Polars.
+// Values are slices or arrow Arrays, which are converted as by series.NewSeries,
+// and Go integers and float32 are widened to int64 and float64. nil elements are nulls.
+// Columns are ordered as in columnOrder, which must name every key of the map,
+// or sorted by name when columnOrder is nil.
+func FromMap(data map[string]any, columnOrder []string) (*DataFrame, error) {
+	if columnOrder == nil {
+		columnOrder = make([]string, 0, len(data))
+		for name := range data {
+			columnOrder = append(columnOrder, name)
+		}
+		sort.Strings(columnOrder)
+	} else if len(columnOrder) != len(data) {
+		return nil, fmt.Errorf("column order has %d columns, but the map has %d", len(columnOrder), len(data))
+	}
+
+	cols := make([]series.Series, len(columnOrder))
+	for i, name := range columnOrder {
+		values, ok := data[name]
+		if !ok {
+			releaseColumns(cols[:i])
+			return nil, &mangoerr.ColumnNotFoundError{Name: name}
+		}
+		s, err := newColumn(name, values, true)
+		if err != nil {
+			releaseColumns(cols[:i])
+			return nil, err
+		}
+		cols[i] = s
+		if i > 0 && s.Len() != cols[0].Len() {
+			releaseColumns(cols[:i+1])
+			return nil, fmt.Errorf("column %s: %w", name, &mangoerr.ShapeMismatchError{What: "length", Expected: cols[0].Len(), Actual: s.Len()})
+		}
+	}
+	return newDataFrameOrRelease(cols)
+}
+
+// FromRows creates a new DataFrame from rows of values, with a column for every name of the header.
+// Values are converted as in FromMap. When inferTypes is true, the types of columns are inferred
+// with coercion, so that a column of numeric strings becomes a numeric column.
+func FromRows(header []string, rows [][]any, inferTypes bool) (*DataFrame, error) {
+	columns := make([][]interface{}, len(header))
+	for i := range columns {
+		columns[i] = make([]interface{}, len(rows))
+	}
+	for i, row := range rows {
+		if len(row) != len(header) {
+			return nil, fmt.Errorf("row %d: %w", i, &mangoerr.ShapeMismatchError{What: "number of values", Expected: len(header), Actual: len(row)})
+		}
+		for j, v := range row {
+			columns[j][i] = v
+		}
+	}
+
+	cols := make([]series.Series, len(header))
+	for i, name := range header {
+		s, err := newColumn(name, columns[i], inferTypes)
+		if err != nil {
+			releaseColumns(cols[:i])
+			return nil, err
+		}
+		cols[i] = s
+	}
+	return newDataFrameOrRelease(cols)
+}
+
+// newColumn creates a Series from a slice or an arrow Array.
+// Slices of mango types are converted as is, slices of other Go numbers are widened,
+// and only the values of other slices, like []any, are boxed and inferred.
+// An empty []any becomes an empty column of the null datatype, since its type cannot be known.
+func newColumn(name string, data any, inferTypes bool) (series.Series, error) {
+	switch data.(type) {
+	case arrow.Array, []int64, []float64, []bool, []string:
+		s, err := series.NewSeriesChecked(name, data)
+		if err != nil {
+			return series.Series{}, fmt.Errorf("column %s: %w", name, err)
+		}
+		return s, nil
+	}
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return series.Series{}, fmt.Errorf("%w: column %s: expected a slice or an arrow Array, got %T", mangoerr.ErrTypeMismatch, name, data)
+	}
+	switch v.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		values := make([]int64, v.Len())
+		for i := range values {
+			values[i] = widenValue(v.Index(i).Interface()).(int64)
+		}
+		return newColumn(name, values, inferTypes)
+	case reflect.Float32:
+		values := make([]float64, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).Float()
+		}
+		return newColumn(name, values, inferTypes)
+	}
+
+	if v.Len() == 0 {
+		return series.NewEmptySeries(name, arrow.Null), nil
+	}
+	values := make([]interface{}, v.Len())
+	allNull := true
+	for i := range values {
+		values[i] = widenValue(v.Index(i).Interface())
+		if _, isNull := values[i].(primitive.Null); !isNull {
+			allNull = false
+		}
+	}
+	if allNull {
+		return series.Series{}, fmt.Errorf("%w: column %s: cannot infer the type of a column without values", mangoerr.ErrTypeMismatch, name)
+	}
+	s, err := series.NewSeriesFromSliceChecked(name, values, nil, inferTypes)
+	if err != nil {
+		return series.Series{}, fmt.Errorf("column %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// widenValue converts a Go value to the types of mango: integers to int64, float32 to float64,
+// and nil or invalid Optionals to Null.
+func widenValue(val interface{}) interface{} {
+	if opt, ok := val.(primitive.Optional[interface{}]); ok {
+		if !opt.Valid {
+			return primitive.Null{}
+		}
+		val = opt.Value
+	}
+	if val == nil {
+		return primitive.Null{}
+	}
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return v.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(v.Uint())
+	case reflect.Float32:
+		return v.Float()
+	}
+	return val
+}
@@ -0,0 +1,93 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func TestFromMap(t *testing.T) {
+	type testCase struct {
+		name   string
+		data   map[string]any
+		order  []string
+		names  []string
+		dtypes []arrow.DataType
+		err    bool
+	}
+	testCases := []testCase{
+		{
+			name:   "sorted",
+			data:   map[string]any{"b": []string{"x", "y", "z"}, "a": []int{1, 2, 3}},
+			names:  []string{"a", "b"},
+			dtypes: []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String},
+		},
+		{
+			name:   "ordered",
+			data:   map[string]any{"b": []float32{1, 2}, "a": []any{true, nil}},
+			order:  []string{"b", "a"},
+			names:  []string{"b", "a"},
+			dtypes: []arrow.DataType{arrow.PrimitiveTypes.Float64, arrow.FixedWidthTypes.Boolean},
+		},
+		{
+			name:   "empty typed slices",
+			data:   map[string]any{"a": []int64{}, "b": []int{}, "c": []string{}},
+			names:  []string{"a", "b", "c"},
+			dtypes: []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String},
+		},
+		{
+			name:   "empty untyped slice",
+			data:   map[string]any{"a": []any{}},
+			names:  []string{"a"},
+			dtypes: []arrow.DataType{arrow.Null},
+		},
+		{name: "mismatched lengths", data: map[string]any{"a": []int64{1, 2}, "b": []int64{1}}, err: true},
+		{name: "missing column", data: map[string]any{"a": []int64{1}}, order: []string{"b"}, err: true},
+		{name: "extra column", data: map[string]any{"a": []int64{1}, "b": []int64{1}}, order: []string{"a"}, err: true},
+		{name: "not a slice", data: map[string]any{"a": 1}, err: true},
+		{name: "only nulls", data: map[string]any{"a": []any{nil}}, err: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			df, err := dataframe.FromMap(tc.data, tc.order)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, df.GetColumnNames(), tc.names)
+			for i, dtype := range tc.dtypes {
+				assert.Equal(t, df.Series[i].DataType(), dtype)
+			}
+		})
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	header := []string{"id", "value"}
+	rows := [][]any{{1, "1.5"}, {2, nil}, {3, "2"}}
+
+	df, err := dataframe.FromRows(header, rows, true)
+	assert.NoError(t, err)
+	assert.Equal(t, df.Series[0].DataType(), arrow.PrimitiveTypes.Int64)
+	assert.Equal(t, df.Series[1].DataType(), arrow.PrimitiveTypes.Float64)
+	assert.Equal(t, df.Series[1].IsNull(), []bool{false, true, false})
+	assert.Equal(t, df.Series[1].ValueExn(2).Value, 2.0)
+
+	df, err = dataframe.FromRows(header, rows, false)
+	assert.NoError(t, err)
+	assert.Equal(t, df.Series[1].DataType(), arrow.BinaryTypes.String)
+
+	df, err = dataframe.FromRows(header, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, df.GetColumnNames(), header)
+	assert.Equal(t, df.Height(), 0)
+
+	_, err = dataframe.FromRows(header, [][]any{{1, "a"}, {2}}, true)
+	assert.Error(t, err)
+	_, err = dataframe.FromRows(header, [][]any{{1, "a"}, {"b", "c"}}, false)
+	assert.Error(t, err)
+}
@@ -1,10 +1,16 @@
 package dataframe
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 
+	"github.com/kstremick/mango/core/internal/parallel"
+	"github.com/kstremick/mango/core/mangoerr"
 	"github.com/kstremick/mango/core/primitive"
 	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/partition"
 )
 
 // DataFrame is a collection of Series
@@ -13,10 +19,56 @@ type DataFrame struct {
 }
 
 // NewDataFrame creates a new DataFrame from a slice of Series
+// It does not check that the Series form a valid DataFrame, see NewDataFrameChecked.
+// The DataFrame takes ownership of the Series, and releases them in Release.
 func NewDataFrame(s []series.Series) *DataFrame {
 	return &DataFrame{s}
 }
 
+// NewDataFrameChecked creates a new DataFrame from a slice of Series,
+// returning an error if they do not form a valid DataFrame.
+// See Validate for the conditions that are checked.
+func NewDataFrameChecked(s []series.Series) (*DataFrame, error) {
+	df := NewDataFrame(append([]series.Series{}, s...))
+	if err := df.Validate(); err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// Validate checks that every column has the same height, and that column names are unique.
+// The returned error reports every violation.
+func (df *DataFrame) Validate() error {
+	var errs []error
+	seen := make(map[string]bool, len(df.Series))
+	for i, s := range df.Series {
+		if s.Len() != df.Height() {
+			errs = append(errs, fmt.Errorf("column %d (%s): %w", i, s.Name, &mangoerr.ShapeMismatchError{What: "height", Expected: df.Height(), Actual: s.Len()}))
+		}
+		if seen[s.Name] {
+			errs = append(errs, fmt.Errorf("column %d has a duplicate name: %s", i, s.Name))
+		}
+		seen[s.Name] = true
+	}
+	return errors.Join(errs...)
+}
+
+// Retain increases the reference count of the data of every column.
+func (df *DataFrame) Retain() {
+	for i := range df.Series {
+		df.Series[i].Retain()
+	}
+}
+
+// Release decreases the reference count of the data of every column,
+// freeing their memory when no other Series or DataFrame hold a reference to them.
+// The DataFrame must not be used after it is released.
+func (df *DataFrame) Release() {
+	for i := range df.Series {
+		df.Series[i].Release()
+	}
+}
+
 // GetColumns returns the columns of the DataFrame
 func (df *DataFrame) GetColumns() []series.Series {
 	return df.Series
@@ -31,7 +83,19 @@ func (df *DataFrame) GetColumnNames() []string {
 	return names
 }
 
+// Column returns the column with the given name.
+// The column shares the reference of the DataFrame, so it must be retained to be used after the DataFrame is released.
+func (df *DataFrame) Column(name string) (series.Series, error) {
+	for _, s := range df.Series {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return series.Series{}, &mangoerr.ColumnNotFoundError{Name: name}
+}
+
 // Select columns from this DataFrame.
+// The columns are shared with the receiver, and retained, so both DataFrames have to be released.
 func (df *DataFrame) Select(colNames ...string) (*DataFrame, error) {
 	series := make([]series.Series, len(colNames))
 	for i, name := range colNames {
@@ -44,9 +108,12 @@ func (df *DataFrame) Select(colNames ...string) (*DataFrame, error) {
 			}
 		}
 		if !found {
-			return nil, errors.New("column not found: " + name)
+			return nil, &mangoerr.ColumnNotFoundError{Name: name}
 		}
 	}
+	for i := range series {
+		series[i].Retain()
+	}
 	return NewDataFrame(series), nil
 }
 
@@ -62,40 +129,222 @@ func (df *DataFrame) Height() int {
 }
 
 // RowSlice returns a row of the DataFrame as a slice of interface{}
-func (df *DataFrame) RowSlice(i int) []interface{} {
+// Returns an error if i is out of bounds.
+func (df *DataFrame) RowSlice(i int) ([]interface{}, error) {
+	if i < 0 || i >= df.Height() {
+		return nil, &mangoerr.OutOfBoundsError{Index: i, Length: df.Height()}
+	}
 	row := make([]interface{}, len(df.Series))
 	for j, s := range df.Series {
-		row[j] = s.ValueExn(i)
+		v, err := s.Value(i)
+		if err != nil {
+			return nil, err
+		}
+		row[j] = v
+	}
+	return row, nil
+}
+
+// RowSliceExn is like RowSlice, but panics on errors.
+func (df *DataFrame) RowSliceExn(i int) []interface{} {
+	row, err := df.RowSlice(i)
+	if err != nil {
+		panic(err)
 	}
 	return row
 }
 
 // Row returns the row as a map from column name to value
-func (df *DataFrame) Row(i int) map[string]primitive.Optional[interface{}] {
+// Returns an error if i is out of bounds.
+func (df *DataFrame) Row(i int) (map[string]primitive.Optional[interface{}], error) {
+	if i < 0 || i >= df.Height() {
+		return nil, &mangoerr.OutOfBoundsError{Index: i, Length: df.Height()}
+	}
 	row := make(map[string]primitive.Optional[interface{}])
 	for _, s := range df.Series {
-		row[s.Name] = s.ValueExn(i)
+		v, err := s.Value(i)
+		if err != nil {
+			return nil, err
+		}
+		row[s.Name] = v
+	}
+	return row, nil
+}
+
+// RowExn is like Row, but panics on errors.
+func (df *DataFrame) RowExn(i int) map[string]primitive.Optional[interface{}] {
+	row, err := df.Row(i)
+	if err != nil {
+		panic(err)
 	}
 	return row
 }
 
+// partition groups the row indices of the DataFrame by the values of the given columns, see partition.Rows.
+func (df *DataFrame) partition(cols ...string) ([][]int64, error) {
+	keyCols := make([]series.Series, len(cols))
+	for i, name := range cols {
+		col, err := df.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		keyCols[i] = col
+	}
+	return partition.Rows(keyCols, df.Height()), nil
+}
+
 // ApplyErr applies a custom/user-defined function (UDF) over the rows of the DataFrame.
 // If any row returns an error, the overall function will return an error
 func (df *DataFrame) ApplyErr(fn ApplyFuncErr) (*series.Series, error) {
-	nRows := df.Height()
-	resultData := make([]primitive.Optional[interface{}], nRows)
+	resultData := make([]primitive.Optional[interface{}], df.Height())
+	if err := df.eachRow(applyRow(df, fn, resultData)); err != nil {
+		return nil, err
+	}
+	return applyResult(resultData)
+}
 
-	for i := 0; i < nRows; i++ {
-		val, err := fn(df.Row(i))
+// MapRows applies a user-defined function over the rows of the DataFrame, returning a Series of type U.
+// Unlike Apply, the type of the result is given by U rather than inferred from the values,
+// and the row is passed as a slice of the values of every column, in order.
+// The slice is reused between calls, so fn must not keep it.
+// If fn returns an error, MapRows stops and returns it.
+func MapRows[U primitive.Primitive](df *DataFrame, name string, fn func(row []primitive.Optional[interface{}]) (primitive.Optional[U], error)) (series.SeriesT[U], error) {
+	b := series.NewBuilderT[U](df.Height())
+	defer b.Release()
+	err := df.eachRow(func(_ int, row []primitive.Optional[interface{}]) error {
+		v, err := fn(row)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		b.AppendOptional(v)
+		return nil
+	})
+	if err != nil {
+		return series.SeriesT[U]{}, err
+	}
+	return b.NewSeries(name), nil
+}
+
+// eachRow calls fn with the values of every row, walking the chunks of the columns in order.
+// The row slice is reused between calls.
+func (df *DataFrame) eachRow(fn func(i int, row []primitive.Optional[interface{}]) error) error {
+	next := df.rowIterator()
+	for i := 0; i < df.Height(); i++ {
+		row, err := next()
+		if err != nil {
+			return err
+		}
+		if err := fn(i, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowIterator returns a function returning the values of the next row every time it is called.
+// The returned slice is reused between calls.
+func (df *DataFrame) rowIterator() func() ([]primitive.Optional[interface{}], error) {
+	iters := make([]*series.Iterator[interface{}], len(df.Series))
+	for j := range df.Series {
+		iters[j] = df.Series[j].Iter()
+	}
+	row := make([]primitive.Optional[interface{}], len(df.Series))
+	return func() ([]primitive.Optional[interface{}], error) {
+		for j, it := range iters {
+			if !it.Next() {
+				if err := it.Err(); err != nil {
+					return nil, err
+				}
+				return nil, &mangoerr.ShapeMismatchError{What: "height of " + df.Series[j].Name, Expected: df.Height(), Actual: it.Index() + 1}
+			}
+			row[j] = it.Value()
+		}
+		return row, nil
+	}
+}
+
+// eachRowParallel calls fn with the values of every row from several goroutines, see parallel.ForEach.
+// Every goroutine reuses its own row slice between calls.
+func (df *DataFrame) eachRowParallel(ctx context.Context, opts series.ParallelOptions, fn func(i int, row []primitive.Optional[interface{}]) error) error {
+	// The slices of every range are released once all ranges are done.
+	var mu sync.Mutex
+	var slices []*DataFrame
+	defer func() {
+		for _, sliced := range slices {
+			sliced.Release()
+		}
+	}()
+	return parallel.ForEach(ctx, df.Height(), opts.Workers, func(start, end int) func(int) error {
+		sliced, err := df.Slice(int64(start), int64(end-start))
+		if err != nil {
+			return func(int) error { return err }
+		}
+		mu.Lock()
+		slices = append(slices, sliced)
+		mu.Unlock()
+		next := sliced.rowIterator()
+		return func(i int) error {
+			row, err := next()
+			if err != nil {
+				return err
+			}
+			return fn(i, row)
+		}
+	})
+}
+
+// ApplyContext is like ApplyErr, but calls fn from several goroutines, so fn must be safe for concurrent use.
+// The rows are split into contiguous ranges, so the result keeps the order of the rows.
+// If fn fails, the returned error is a *mangoerr.RowError for the first failing row.
+// If ctx is cancelled, ApplyContext stops and returns the error of ctx.
+func (df *DataFrame) ApplyContext(ctx context.Context, fn ApplyFuncErr, opts series.ParallelOptions) (*series.Series, error) {
+	resultData := make([]primitive.Optional[interface{}], df.Height())
+	if err := df.eachRowParallel(ctx, opts, applyRow(df, fn, resultData)); err != nil {
+		return nil, err
+	}
+	return applyResult(resultData)
+}
+
+// applyRow returns a function calling fn with every row as a map, and storing the results.
+func applyRow(df *DataFrame, fn ApplyFuncErr, results []primitive.Optional[interface{}]) func(int, []primitive.Optional[interface{}]) error {
+	return func(i int, values []primitive.Optional[interface{}]) error {
+		row := make(map[string]primitive.Optional[interface{}], len(df.Series))
+		for j, v := range values {
+			row[df.Series[j].Name] = v
+		}
+		val, err := fn(row)
+		if err != nil {
+			return err
 		}
-		resultData[i] = primitive.Some(val)
+		results[i] = primitive.Some(val)
+		return nil
+	}
+}
+
+// applyResult returns the Series of the results of Apply, inferring their type.
+func applyResult(results []primitive.Optional[interface{}]) (*series.Series, error) {
+	ser, err := series.NewSeriesChecked("", results)
+	if err != nil {
+		return nil, err
 	}
-	ser := series.NewSeries("", resultData)
 	return &ser, nil
 }
 
+// MapRowsContext is like MapRows, but calls fn from several goroutines, see ApplyContext.
+// Every goroutine reuses its own row slice between calls.
+func MapRowsContext[U primitive.Primitive](ctx context.Context, df *DataFrame, name string, fn func(row []primitive.Optional[interface{}]) (primitive.Optional[U], error), opts series.ParallelOptions) (series.SeriesT[U], error) {
+	results := make([]primitive.Optional[U], df.Height())
+	err := df.eachRowParallel(ctx, opts, func(i int, row []primitive.Optional[interface{}]) error {
+		v, err := fn(row)
+		results[i] = v
+		return err
+	})
+	if err != nil {
+		return series.SeriesT[U]{}, err
+	}
+	return series.NewSeriesTFromOptionals(name, results), nil
+}
+
 // Apply applies a custom/user-defined function (UDF) over the rows of the DataFrame.m
 func (df *DataFrame) Apply(fn ApplyFunc) *series.Series {
 	wrappedFunc := func(row map[string]primitive.Optional[interface{}]) (interface{}, error) {
@@ -110,21 +359,53 @@ func (df *DataFrame) Apply(fn ApplyFunc) *series.Series {
 	return ser
 }
 
-// With_columns adds clumns to the dataframe.
+// WithColumns returns a new DataFrame with added columns.
 // Added columns will replace existing columns with the same name.
+// The receiver is not modified, and the columns share their data with it and with s.
+// They are retained, so the receiver and s still have to be released.
+// The heights of the columns are not checked, see WithColumnsChecked.
 func (df *DataFrame) WithColumns(s ...*series.Series) *DataFrame {
+	columns := append([]series.Series{}, df.Series...)
 	for _, ser := range s {
 		found := false
-		for i, s := range df.Series {
+		for i, s := range columns {
 			if s.Name == ser.Name {
-				df.Series[i] = *ser
+				columns[i] = *ser
 				found = true
 				break
 			}
 		}
 		if !found {
-			df.Series = append(df.Series, *ser)
+			columns = append(columns, *ser)
+		}
+	}
+	for i := range columns {
+		columns[i].Retain()
+	}
+	return NewDataFrame(columns)
+}
+
+// WithColumnsChecked is like WithColumns, but returns an error if the result is not a valid DataFrame,
+// for example if an added column does not have the height of the receiver. See Validate.
+func (df *DataFrame) WithColumnsChecked(s ...*series.Series) (*DataFrame, error) {
+	out := df.WithColumns(s...)
+	if err := out.Validate(); err != nil {
+		out.Release()
+		return nil, err
+	}
+	return out, nil
+}
+
+// Rechunk aggregates the chunks of every column to a contiguous array of memory, in place.
+//
+// The previous data of the columns is released, so copies of the columns that were not retained,
+// like col := df.Series[0] or the Series returned by Column, are left empty, see series.Series.Rechunk.
+// DataFrames sharing the columns, like those returned by Select, hold their own reference and are unaffected.
+func (df *DataFrame) Rechunk() error {
+	for i := range df.Series {
+		if err := df.Series[i].Rechunk(); err != nil {
+			return fmt.Errorf("column %s: %w", df.Series[i].Name, err)
 		}
 	}
-	return df
+	return nil
 }
@@ -1,9 +1,13 @@
 package dataframe_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/mangoerr"
 	"github.com/kstremick/mango/core/primitive"
 	"github.com/kstremick/mango/core/series"
 
@@ -51,7 +55,183 @@ func TestSelect(t *testing.T) {
 	}
 	doubleSeries := df.Apply(doubleFunc)
 	doubleSeries.Rename("PassengerIdDoubled")
+	for i, id := range []int64{1, 2, 3} {
+		assert.Equal(t, id*2, doubleSeries.ValueExn(i).Value)
+	}
 	df = df.WithColumns(doubleSeries)
 
 	assert.Equal(t, df.GetColumnNames(), []string{"PassengerId", "Survived", "Pclass", "Name", "PassengerIdDoubled"})
 }
+
+func TestWithColumnsDoesNotMutate(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []int64{3, 4}),
+	})
+	replaced := series.NewSeries("a", []string{"x", "y"})
+	added := series.NewSeries("c", []bool{true, false})
+
+	out := df.WithColumns(&replaced, &added)
+	assert.DeepEqual(t, []string{"a", "b", "c"}, out.GetColumnNames())
+	assert.DeepEqual(t, []string{"a", "b"}, df.GetColumnNames())
+	a, _ := df.Column("a")
+	assert.Equal(t, int64(1), a.ValueExn(0).Value)
+	a, _ = out.Column("a")
+	assert.Equal(t, "x", a.ValueExn(0).Value)
+}
+
+func TestWithColumnsChecked(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []int64{3, 4}),
+	})
+	added := series.NewSeries("c", []int64{5, 6})
+	out, err := df.WithColumnsChecked(&added)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"a", "b", "c"}, out.GetColumnNames())
+
+	type testCase struct {
+		name string
+		s    series.Series
+	}
+	testCases := []testCase{
+		{"added column too short", series.NewSeries("c", []int64{5})},
+		{"added column too long", series.NewSeries("c", []int64{5, 6, 7})},
+		{"replaced column", series.NewSeries("a", []int64{1, 2, 3})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := df.WithColumnsChecked(&tc.s)
+			assert.True(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []int64{3, 4}),
+	}
+	df, err := dataframe.NewDataFrameChecked(valid)
+	assert.NoError(t, err)
+	assert.NoError(t, df.Validate())
+
+	invalid := []series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []int64{3}),
+		series.NewSeries("a", []int64{5, 6}),
+		series.NewSeries("c", []int64{7, 8, 9}),
+	}
+	_, err = dataframe.NewDataFrameChecked(invalid)
+	assert.Error(t, err)
+
+	err = dataframe.NewDataFrame(invalid).Validate()
+	assert.Error(t, err)
+	assert.Equal(t, 3, len(err.(interface{ Unwrap() []error }).Unwrap()))
+}
+
+func TestDataFrameErrors(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []string{"x", "y"}),
+	})
+
+	_, err := df.Column("c")
+	var notFound *mangoerr.ColumnNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, notFound.Name, "c")
+	_, err = df.Select("a", "c")
+	assert.True(t, errors.Is(err, mangoerr.ErrColumnNotFound))
+
+	_, err = df.Row(2)
+	assert.True(t, errors.Is(err, mangoerr.ErrOutOfBounds))
+	_, err = df.RowSlice(-1)
+	assert.True(t, errors.Is(err, mangoerr.ErrOutOfBounds))
+	row, err := df.RowSlice(1)
+	assert.NoError(t, err)
+	assert.Equal(t, row, []interface{}{primitive.Some[interface{}](int64(2)), primitive.Some[interface{}]("y")})
+
+	short := dataframe.NewDataFrame([]series.Series{series.NewSeries("c", []int64{1})})
+	_, err = dataframe.Concat([]*dataframe.DataFrame{df, short}, dataframe.ConcatHorizontal)
+	assert.True(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+	_, err = dataframe.NewDataFrameChecked(append(df.Series, short.Series...))
+	assert.True(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+}
+
+func TestMapRows(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2, 3}),
+		series.NewSeriesFromSlice("b", []float64{0.5, 0, 1.5}, []bool{true, false, true}, false),
+	})
+	sums, err := dataframe.MapRows(df, "sum", func(row []primitive.Optional[interface{}]) (primitive.Optional[float64], error) {
+		if !row[1].Valid {
+			return primitive.None[float64](), nil
+		}
+		return primitive.Some(float64(row[0].Value.(int64)) + row[1].Value.(float64)), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", sums.Name)
+	vals, valids := sums.ToSliceExn()
+	assert.Equal(t, []float64{1.5, 0, 4.5}, vals)
+	assert.Equal(t, []bool{true, false, true}, valids)
+
+	_, err = dataframe.MapRows(df, "err", func(row []primitive.Optional[interface{}]) (primitive.Optional[int64], error) {
+		return primitive.None[int64](), errors.New("failed")
+	})
+	assert.Error(t, err)
+}
+
+func TestApplyContext(t *testing.T) {
+	n := 1000
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	df := dataframe.NewDataFrame([]series.Series{series.NewSeries("id", ids)})
+	ctx := context.Background()
+	opts := series.ParallelOptions{Workers: 8}
+
+	doubled, err := df.ApplyContext(ctx, func(row map[string]primitive.Optional[interface{}]) (interface{}, error) {
+		return row["id"].Value.(int64) * 2, nil
+	}, opts)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, int64(i*2), doubled.ValueExn(i).Value)
+	}
+
+	_, err = df.ApplyContext(ctx, func(row map[string]primitive.Optional[interface{}]) (interface{}, error) {
+		if id := row["id"].Value.(int64); id%100 == 37 {
+			return nil, fmt.Errorf("bad id %d", id)
+		}
+		return 0, nil
+	}, opts)
+	var rowErr *mangoerr.RowError
+	assert.That(t, errors.As(err, &rowErr))
+	assert.Equal(t, 37, rowErr.Row)
+	assert.Equal(t, "row 37: bad id 37", err.Error())
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = df.ApplyContext(cancelled, func(row map[string]primitive.Optional[interface{}]) (interface{}, error) {
+		return 0, nil
+	}, opts)
+	assert.That(t, errors.Is(err, context.Canceled))
+}
+
+func TestMapRowsContext(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2, 3}),
+		series.NewSeriesFromSlice("b", []float64{0.5, 0, 1.5}, []bool{true, false, true}, false),
+	})
+	sums, err := dataframe.MapRowsContext(context.Background(), df, "sum", func(row []primitive.Optional[interface{}]) (primitive.Optional[float64], error) {
+		if !row[1].Valid {
+			return primitive.None[float64](), nil
+		}
+		return primitive.Some(float64(row[0].Value.(int64)) + row[1].Value.(float64)), nil
+	}, series.ParallelOptions{Workers: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", sums.Name)
+	vals, valids := sums.ToSliceExn()
+	assert.Equal(t, []float64{1.5, 0, 4.5}, vals)
+	assert.Equal(t, []bool{true, false, true}, valids)
+}
@@ -0,0 +1,117 @@
+package dataframe
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+var defaultPercentiles = []float64{0.25, 0.5, 0.75}
+
+// Describe returns summary statistics of every column, one statistic per row.
+// The first column, "statistic", names the statistics:
+// count, null_count, mean, std, min, one row per percentile (25%, 50% and 75% by default), max, unique, top and freq.
+// Numeric columns become float64 columns holding count through max.
+// Other columns become string columns holding count, null_count, and the number of unique values,
+// the most frequent value (top) and its frequency (freq).
+// Statistics that do not apply to a column are null.
+func (df *DataFrame) Describe(percentiles ...float64) (*DataFrame, error) {
+	if len(percentiles) == 0 {
+		percentiles = defaultPercentiles
+	}
+	statistics := []string{"count", "null_count", "mean", "std", "min"}
+	for _, p := range percentiles {
+		if p < 0 || p > 1 {
+			return nil, fmt.Errorf("percentile must be between 0 and 1, got %v", p)
+		}
+		statistics = append(statistics, strconv.FormatFloat(p*100, 'f', -1, 64)+"%")
+	}
+	statistics = append(statistics, "max", "unique", "top", "freq")
+
+	out := []series.Series{series.NewSeries("statistic", statistics)}
+	for _, s := range df.Series {
+		var described series.Series
+		var err error
+		if s.Type() == arrow.INT64 || s.Type() == arrow.FLOAT64 {
+			described, err = describeNumeric(s, percentiles)
+		} else {
+			described = describeCategorical(s, len(percentiles))
+		}
+		if err != nil {
+			releaseColumns(out)
+			return nil, err
+		}
+		out = append(out, described)
+	}
+	return NewDataFrame(out), nil
+}
+
+// describeNumeric computes the statistics of a numeric column.
+func describeNumeric(s series.Series, percentiles []float64) (series.Series, error) {
+	count := float64(s.Len() - s.NullN())
+	stats := []primitive.Optional[float64]{primitive.Some(count), primitive.Some(float64(s.NullN()))}
+	for _, fn := range []func() (primitive.Optional[float64], error){s.Mean, s.Std, s.Min} {
+		v, err := fn()
+		if err != nil {
+			return series.Series{}, err
+		}
+		stats = append(stats, v)
+	}
+	for _, p := range percentiles {
+		v, err := s.Quantile(p)
+		if err != nil {
+			return series.Series{}, err
+		}
+		stats = append(stats, v)
+	}
+	max, err := s.Max()
+	if err != nil {
+		return series.Series{}, err
+	}
+	stats = append(stats, max, primitive.None[float64](), primitive.None[float64](), primitive.None[float64]())
+
+	vals := make([]float64, len(stats))
+	valids := make([]bool, len(stats))
+	for i, v := range stats {
+		vals[i], valids[i] = v.Value, v.Valid
+	}
+	return series.NewSeriesTFromTSlice(s.Name, vals, valids).Series, nil
+}
+
+// describeCategorical computes the statistics of a non-numeric column, formatted as strings.
+func describeCategorical(s series.Series, nPercentiles int) series.Series {
+	counts := make(map[string]int)
+	var top string
+	topCount := 0
+	for i := 0; i < s.Len(); i++ {
+		v := s.ValueExn(i)
+		if !v.Valid {
+			continue
+		}
+		key := v.String()
+		counts[key]++
+		// Ties are broken by first appearance.
+		if counts[key] > topCount {
+			top, topCount = key, counts[key]
+		}
+	}
+
+	nStats := 9 + nPercentiles
+	vals := make([]string, nStats)
+	valids := make([]bool, nStats)
+	set := func(i int, v string) {
+		vals[i], valids[i] = v, true
+	}
+	set(0, strconv.Itoa(s.Len()-s.NullN()))
+	set(1, strconv.Itoa(s.NullN()))
+	set(nStats-3, strconv.Itoa(len(counts)))
+	if len(counts) > 0 {
+		set(nStats-2, top)
+		set(nStats-1, strconv.Itoa(topCount))
+	}
+	return series.NewSeriesTFromTSlice(s.Name, vals, valids).Series
+}
@@ -0,0 +1,53 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/io"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []interface{}{int64(1), int64(2), int64(3), primitive.Null{}, int64(4)}),
+		series.NewSeries("y", []string{"a", "b", "b", "c", "c"}),
+	})
+
+	described, err := df.Describe()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"statistic", "x", "y"}, described.GetColumnNames())
+
+	statistic, _ := described.Column("statistic")
+	assert.DeepEqual(t, []interface{}{"count", "null_count", "mean", "std", "min", "25%", "50%", "75%", "max", "unique", "top", "freq"}, values(statistic))
+
+	x, _ := described.Column("x")
+	assert.Equal(t, arrow.FLOAT64, x.Type())
+	stdX := x.ValueExn(3).Value.(float64)
+	assert.DeepEqual(t, []interface{}{4.0, 1.0, 2.5, stdX, 1.0, 1.75, 2.5, 3.25, 4.0, nil, nil, nil}, values(x))
+
+	y, _ := described.Column("y")
+	assert.Equal(t, arrow.STRING, y.Type())
+	assert.DeepEqual(t, []interface{}{"5", "0", nil, nil, nil, nil, nil, nil, nil, "3", "b", "2"}, values(y))
+
+	described, err = df.Describe(0.1, 0.9)
+	assert.NoError(t, err)
+	statistic, _ = described.Column("statistic")
+	assert.DeepEqual(t, []interface{}{"count", "null_count", "mean", "std", "min", "10%", "90%", "max", "unique", "top", "freq"}, values(statistic))
+
+	_, err = df.Describe(1.5)
+	assert.Error(t, err)
+}
+
+func TestDescribeTitanic(t *testing.T) {
+	df, err := io.ReadCsvFile("testdata/titanic.csv")
+	assert.NoError(t, err)
+	described, err := df.Describe()
+	assert.NoError(t, err)
+	assert.Equal(t, df.Width()+1, described.Width())
+	assert.Equal(t, 12, described.Height())
+}
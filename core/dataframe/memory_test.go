@@ -0,0 +1,135 @@
+package dataframe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/memtest"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func TestRelease(t *testing.T) {
+	mem := memtest.CheckedAllocator(t)
+
+	df, err := dataframe.FromMap(map[string]any{
+		"a": []int64{1, 2, 3},
+		"b": []float64{1.5, 2.5, 3.5},
+		"c": []any{"x", nil, "z"},
+	}, nil)
+	assert.NoError(t, err)
+	defer df.Release()
+	assert.That(t, mem.CurrentAlloc() > 0)
+
+	other := dataframe.NewDataFrame([]series.Series{series.NewSeries("d", []bool{true, false, true})})
+	defer other.Release()
+	mask := series.NewSeriesTFromTSlice("mask", []bool{true, false, true}, nil)
+	defer mask.Release()
+	indices := series.NewSeriesTFromTSlice("indices", []int64{2, 0}, nil)
+	defer indices.Release()
+
+	memtest.Run(t, []memtest.Case[*dataframe.DataFrame]{
+		{Name: "select", Fn: func() (*dataframe.DataFrame, error) { return df.Select("c", "a") }},
+		{Name: "with columns", Fn: func() (*dataframe.DataFrame, error) { return df.WithColumns(&other.Series[0]), nil }},
+		{Name: "drop", Fn: func() (*dataframe.DataFrame, error) { return df.Drop("b") }},
+		{Name: "rename", Fn: func() (*dataframe.DataFrame, error) { return df.Rename(map[string]string{"a": "A"}) }},
+		{Name: "reorder", Fn: func() (*dataframe.DataFrame, error) { return df.Reorder("c") }},
+		{Name: "cast columns", Fn: func() (*dataframe.DataFrame, error) {
+			return df.CastColumns(map[string]arrow.DataType{"a": arrow.PrimitiveTypes.Float64})
+		}},
+		{Name: "filter", Fn: func() (*dataframe.DataFrame, error) { return df.Filter(&mask) }},
+		{Name: "take", Fn: func() (*dataframe.DataFrame, error) { return df.Take(&indices) }},
+		{Name: "head", Fn: func() (*dataframe.DataFrame, error) { return df.Head(2), nil }},
+		{Name: "melt", Fn: func() (*dataframe.DataFrame, error) { return df.Melt([]string{"c"}, []string{"a", "b"}, "", "") }},
+		{Name: "concat vertical", Fn: func() (*dataframe.DataFrame, error) {
+			return dataframe.Concat([]*dataframe.DataFrame{df, df}, dataframe.ConcatVertical)
+		}},
+		{Name: "concat horizontal", Fn: func() (*dataframe.DataFrame, error) {
+			return dataframe.Concat([]*dataframe.DataFrame{df, other}, dataframe.ConcatHorizontal)
+		}},
+		{Name: "concat diagonal", Fn: func() (*dataframe.DataFrame, error) {
+			return dataframe.Concat([]*dataframe.DataFrame{df, other}, dataframe.ConcatDiagonal)
+		}},
+		{Name: "concat and rechunk", Fn: func() (*dataframe.DataFrame, error) {
+			defer func(threshold int) { dataframe.ConcatRechunkThreshold = threshold }(dataframe.ConcatRechunkThreshold)
+			dataframe.ConcatRechunkThreshold = 1
+			return dataframe.Concat([]*dataframe.DataFrame{df, df}, dataframe.ConcatVertical)
+		}},
+		{Name: "sample", Fn: func() (*dataframe.DataFrame, error) { return df.Sample(2, true, 1) }},
+		{Name: "shuffle", Fn: func() (*dataframe.DataFrame, error) { return df.Shuffle(1) }},
+		{Name: "pivot", Fn: func() (*dataframe.DataFrame, error) { return df.Pivot("c", "a", "b", dataframe.AggSum) }},
+		{Name: "apply in parallel", Fn: func() (*dataframe.DataFrame, error) {
+			out, err := df.ApplyContext(context.Background(), func(row map[string]primitive.Optional[interface{}]) (interface{}, error) {
+				return row["a"].Value, nil
+			}, series.ParallelOptions{Workers: 2})
+			if err != nil {
+				return nil, err
+			}
+			return dataframe.NewDataFrame([]series.Series{*out}), nil
+		}},
+		{Name: "rechunk", Fn: func() (*dataframe.DataFrame, error) {
+			out, err := dataframe.Concat([]*dataframe.DataFrame{df, df}, dataframe.ConcatVertical)
+			if err != nil {
+				return nil, err
+			}
+			return out, out.Rechunk()
+		}},
+	}, (*dataframe.DataFrame).Release)
+}
+
+func TestReleaseOnError(t *testing.T) {
+	memtest.CheckedAllocator(t)
+
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2, 3}),
+		series.NewSeries("b", []float64{1.5, 2.5, 3.5}),
+		series.NewSeries("c", []string{"x", "y", "z"}),
+	})
+	defer df.Release()
+	strs := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{4}),
+		series.NewSeries("b", []string{"w"}),
+		series.NewSeries("c", []string{"v"}),
+	})
+	defer strs.Release()
+
+	type testCase struct {
+		name string
+		fn   func() (*dataframe.DataFrame, error)
+	}
+	testCases := []testCase{
+		{"from map of different lengths", func() (*dataframe.DataFrame, error) {
+			return dataframe.FromMap(map[string]any{"a": []int64{1, 2}, "b": []int64{1}}, nil)
+		}},
+		{"from map with a missing column", func() (*dataframe.DataFrame, error) {
+			return dataframe.FromMap(map[string]any{"a": []int64{1, 2}, "b": []int64{1, 2}}, []string{"a", "z"})
+		}},
+		{"from rows with a null column", func() (*dataframe.DataFrame, error) {
+			return dataframe.FromRows([]string{"a", "b"}, [][]any{{1, nil}}, false)
+		}},
+		{"rename to a duplicate", func() (*dataframe.DataFrame, error) { return df.Rename(map[string]string{"a": "b"}) }},
+		{"reorder a duplicate", func() (*dataframe.DataFrame, error) { return df.Reorder("c", "c") }},
+		{"cast columns", func() (*dataframe.DataFrame, error) {
+			return df.CastColumns(map[string]arrow.DataType{"b": arrow.PrimitiveTypes.Int64, "c": arrow.PrimitiveTypes.Int64})
+		}},
+		{"with columns of a different height", func() (*dataframe.DataFrame, error) {
+			return df.WithColumnsChecked(&strs.Series[1])
+		}},
+		{"concat vertical of different types", func() (*dataframe.DataFrame, error) {
+			return dataframe.Concat([]*dataframe.DataFrame{df, strs}, dataframe.ConcatVertical)
+		}},
+		{"concat horizontal of duplicates", func() (*dataframe.DataFrame, error) {
+			return dataframe.Concat([]*dataframe.DataFrame{df, df}, dataframe.ConcatHorizontal)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.fn()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -44,16 +44,19 @@ func (df *DataFrame) String() string {
 
 	var maxLengths []int
 	var header, types []string
+	cells := make([][]string, len(columns))
 
-	for _, col := range columns {
+	for colI, col := range columns {
 		maxLength := len(col.Name)
 		header = append(header, col.Name)
 		types = append(types, shortType(col.DataType()))
 
-		for i := 0; i < col.Len(); i++ {
-			length := len(fmt.Sprintf("%v", col.ValueExn(i).String()))
-			if length > maxLength {
-				maxLength = length
+		cells[colI] = make([]string, 0, col.Len())
+		for it := col.Iter(); it.Next(); {
+			cell := it.Value().String()
+			cells[colI] = append(cells[colI], cell)
+			if len(cell) > maxLength {
+				maxLength = len(cell)
 			}
 		}
 
@@ -71,9 +74,8 @@ func (df *DataFrame) String() string {
 	nRows := columns[0].Len()
 	for i := 0; i < nRows; i++ {
 		var rowData []string
-		for colI, col := range columns {
-			value := col.ValueExn(i)
-			rowData = append(rowData, fmt.Sprintf("%-*v", maxLengths[colI], value.String()))
+		for colI := range columns {
+			rowData = append(rowData, fmt.Sprintf("%-*v", maxLengths[colI], cells[colI][i]))
 		}
 		sb.WriteString(formatRow(rowData, maxLengths) + "\n")
 		sb.WriteString(sepRow)
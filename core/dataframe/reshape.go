@@ -0,0 +1,294 @@
+package dataframe
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// AggFunc aggregates a group of values into a Series holding a single value.
+type AggFunc func(s series.Series) (series.Series, error)
+
+var (
+	// AggFirst keeps the first value of the group.
+	AggFirst AggFunc = func(s series.Series) (series.Series, error) {
+		return s.Slice(0, 1)
+	}
+	// AggLast keeps the last value of the group.
+	AggLast AggFunc = func(s series.Series) (series.Series, error) {
+		return s.Slice(-1, 1)
+	}
+	// AggCount counts the non-null values of the group.
+	AggCount AggFunc = func(s series.Series) (series.Series, error) {
+		return series.NewSeries(s.Name, []int64{int64(s.Len() - s.NullN())}), nil
+	}
+	// AggSum sums the values of a numeric group.
+	AggSum AggFunc = func(s series.Series) (series.Series, error) {
+		sum, err := s.Sum()
+		return float64Series(s.Name, primitive.Some(sum)), err
+	}
+	// AggMean averages the values of a numeric group.
+	AggMean = aggFloat64((*series.Series).Mean)
+	// AggMin keeps the minimum of a numeric group.
+	AggMin = aggFloat64((*series.Series).Min)
+	// AggMax keeps the maximum of a numeric group.
+	AggMax = aggFloat64((*series.Series).Max)
+	// AggMedian keeps the median of a numeric group.
+	AggMedian = aggFloat64((*series.Series).Median)
+)
+
+func aggFloat64(fn func(s *series.Series) (primitive.Optional[float64], error)) AggFunc {
+	return func(s series.Series) (series.Series, error) {
+		v, err := fn(&s)
+		if err != nil {
+			return series.Series{}, err
+		}
+		return float64Series(s.Name, v), nil
+	}
+}
+
+// float64Series creates a float64 Series holding the single value v.
+func float64Series(name string, v primitive.Optional[float64]) series.Series {
+	return series.NewSeriesTFromTSlice(name, []float64{v.Value}, []bool{v.Valid}).Series
+}
+
+// Pivot reshapes the DataFrame from long to wide format.
+// Each distinct value of the index column becomes a row, in order of first appearance,
+// and each distinct value of the columns column becomes a column named after its string form, in sorted order.
+// The cells hold the values of the values column aggregated with aggFunc, or null if there are none.
+func (df *DataFrame) Pivot(index, columns, values string, aggFunc AggFunc) (*DataFrame, error) {
+	indexCol, err := df.Column(index)
+	if err != nil {
+		return nil, err
+	}
+	columnsCol, err := df.Column(columns)
+	if err != nil {
+		return nil, err
+	}
+	valuesCol, err := df.Column(values)
+	if err != nil {
+		return nil, err
+	}
+
+	rowGroups, err := df.partition(index)
+	if err != nil {
+		return nil, err
+	}
+	colGroups, err := df.partition(columns)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(colGroups, func(a, b int) bool {
+		return primitive.Compare(columnsCol.ValueExn(int(colGroups[a][0])), columnsCol.ValueExn(int(colGroups[b][0]))) < 0
+	})
+	colOf := make([]int, df.Height())
+	for c, rows := range colGroups {
+		for _, row := range rows {
+			colOf[row] = c
+		}
+	}
+
+	firstRows := make([]int64, len(rowGroups))
+	// cells[c][r] holds the rows of the values column in the cell at column c and row r.
+	cells := make([][][]int64, len(colGroups))
+	for c := range cells {
+		cells[c] = make([][]int64, len(rowGroups))
+	}
+	for r, rows := range rowGroups {
+		firstRows[r] = rows[0]
+		for _, row := range rows {
+			c := colOf[row]
+			cells[c][r] = append(cells[c][r], row)
+		}
+	}
+
+	for _, rows := range colGroups {
+		key := columnsCol.ValueExn(int(rows[0]))
+		if !key.Valid {
+			return nil, fmt.Errorf("cannot pivot null values of column %s into a column name", columns)
+		}
+		if key.String() == index {
+			return nil, fmt.Errorf("pivoted column %s has the same name as the index", index)
+		}
+	}
+
+	indices := series.NewSeriesTFromTSlice("", firstRows, nil)
+	defer indices.Release()
+	indexOut, err := indexCol.Take(&indices)
+	if err != nil {
+		return nil, err
+	}
+	out := []series.Series{indexOut}
+	for c, rows := range colGroups {
+		col, err := pivotColumn(valuesCol, cells[c], aggFunc)
+		if err != nil {
+			for i := range out {
+				out[i].Release()
+			}
+			return nil, err
+		}
+		col.Rename(columnsCol.ValueExn(int(rows[0])).String())
+		out = append(out, col)
+	}
+	return NewDataFrame(out), nil
+}
+
+// pivotColumn aggregates the values in each cell of a pivoted column into a single Series.
+func pivotColumn(values series.Series, cells [][]int64, aggFunc AggFunc) (series.Series, error) {
+	var dtype arrow.DataType
+	aggregated := make([]*series.Series, len(cells))
+	defer func() {
+		for _, res := range aggregated {
+			if res != nil {
+				res.Release()
+			}
+		}
+	}()
+	for r, rows := range cells {
+		if rows == nil {
+			continue
+		}
+		res, err := aggregateRows(values, rows, aggFunc)
+		if err != nil {
+			return series.Series{}, err
+		}
+		aggregated[r] = &res
+		if res.Len() != 1 {
+			return series.Series{}, &mangoerr.ShapeMismatchError{What: "length of aggregation", Expected: 1, Actual: res.Len()}
+		}
+		if dtype == nil {
+			dtype = res.DataType()
+		} else if !arrow.TypeEqual(dtype, res.DataType()) {
+			return series.Series{}, fmt.Errorf("aggregations have different types: %w", &mangoerr.TypeMismatchError{Expected: dtype.String(), Actual: res.DataType()})
+		}
+	}
+
+	mem := series.DefaultAllocator()
+	chunks := make([]arrow.Array, 0, len(cells))
+	for _, res := range aggregated {
+		if res == nil {
+			null := array.MakeArrayOfNull(mem, dtype, 1)
+			defer null.Release()
+			chunks = append(chunks, null)
+			continue
+		}
+		chunks = append(chunks, res.Chunks()...)
+	}
+	arr, err := array.Concatenate(chunks, mem)
+	if err != nil {
+		return series.Series{}, err
+	}
+	defer arr.Release()
+	return series.NewSeriesFromArray(values.Name, arr), nil
+}
+
+// aggregateRows aggregates the values at the given rows with aggFunc.
+func aggregateRows(values series.Series, rows []int64, aggFunc AggFunc) (series.Series, error) {
+	indices := series.NewSeriesTFromTSlice("", rows, nil)
+	defer indices.Release()
+	group, err := values.Take(&indices)
+	if err != nil {
+		return series.Series{}, err
+	}
+	defer group.Release()
+	return aggFunc(group)
+}
+
+// Melt reshapes the DataFrame from wide to long format.
+// Every column in valueVars becomes a set of rows, where the varName column holds the name of the column
+// and the valueName column holds its values. The columns in idVars are repeated for every set of rows.
+// If valueVars is empty, every column not in idVars is used.
+// varName and valueName default to "variable" and "value".
+// Value columns of different types are cast to float64 if they are all numeric, and to string otherwise.
+func (df *DataFrame) Melt(idVars, valueVars []string, varName, valueName string) (*DataFrame, error) {
+	if varName == "" {
+		varName = "variable"
+	}
+	if valueName == "" {
+		valueName = "value"
+	}
+	idCols, err := df.Select(idVars...)
+	if err != nil {
+		return nil, err
+	}
+	defer idCols.Release()
+	if len(valueVars) == 0 {
+		for _, name := range df.GetColumnNames() {
+			if _, err := idCols.Column(name); err != nil {
+				valueVars = append(valueVars, name)
+			}
+		}
+	}
+	valueCols, err := df.Select(valueVars...)
+	if err != nil {
+		return nil, err
+	}
+	defer valueCols.Release()
+	if len(valueVars) == 0 {
+		return nil, fmt.Errorf("no value columns to melt")
+	}
+
+	out := make([]series.Series, 0, len(idVars)+2)
+	for _, col := range idCols.Series {
+		out = append(out, repeatChunks(col, len(valueVars)))
+	}
+
+	names := make([]string, 0, df.Height()*len(valueVars))
+	for _, name := range valueVars {
+		for i := 0; i < df.Height(); i++ {
+			names = append(names, name)
+		}
+	}
+	out = append(out, series.NewSeriesTFromTSlice(varName, names, nil).Series)
+
+	dtype := meltType(valueCols.Series)
+	var chunks []arrow.Array
+	for _, col := range valueCols.Series {
+		casted, err := col.Cast(dtype)
+		if err != nil {
+			releaseColumns(out)
+			return nil, err
+		}
+		defer casted.Release()
+		chunks = append(chunks, casted.Chunks()...)
+	}
+	out = append(out, series.NewSeriesFromChunked(valueName, arrow.NewChunked(dtype, chunks)))
+	return NewDataFrame(out), nil
+}
+
+// repeatChunks returns a Series holding the values of s repeated n times, without copying them.
+func repeatChunks(s series.Series, n int) series.Series {
+	chunks := make([]arrow.Array, 0, n*s.NumChunks())
+	for i := 0; i < n; i++ {
+		chunks = append(chunks, s.Chunks()...)
+	}
+	return series.NewSeriesFromChunked(s.Name, arrow.NewChunked(s.DataType(), chunks))
+}
+
+// meltType returns the type that all the given columns can be cast to.
+func meltType(cols []series.Series) arrow.DataType {
+	dtype := cols[0].DataType()
+	numeric := true
+	same := true
+	for _, col := range cols {
+		if !arrow.TypeEqual(dtype, col.DataType()) {
+			same = false
+		}
+		if col.Type() != arrow.INT64 && col.Type() != arrow.FLOAT64 {
+			numeric = false
+		}
+	}
+	switch {
+	case same:
+		return dtype
+	case numeric:
+		return arrow.PrimitiveTypes.Float64
+	}
+	return arrow.BinaryTypes.String
+}
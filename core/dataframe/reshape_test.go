@@ -0,0 +1,94 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+// values returns the values of a Series, with nulls as nil.
+func values(s series.Series) []interface{} {
+	ret := make([]interface{}, s.Len())
+	for i := range ret {
+		v := s.ValueExn(i)
+		if v.Valid {
+			ret[i] = v.Value
+		}
+	}
+	return ret
+}
+
+func TestPivot(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("day", []string{"mon", "mon", "tue", "mon", "wed"}),
+		series.NewSeries("event", []int64{2, 1, 1, 2, 10}),
+		series.NewSeries("count", []float64{1, 2, 3, 4, 5}),
+	})
+
+	wide, err := df.Pivot("day", "event", "count", dataframe.AggSum)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"day", "1", "2", "10"}, wide.GetColumnNames())
+	assert.Equal(t, 3, wide.Height())
+
+	day, _ := wide.Column("day")
+	assert.DeepEqual(t, []interface{}{"mon", "tue", "wed"}, values(day))
+	one, _ := wide.Column("1")
+	assert.DeepEqual(t, []interface{}{2.0, 3.0, nil}, values(one))
+	two, _ := wide.Column("2")
+	assert.DeepEqual(t, []interface{}{5.0, nil, nil}, values(two))
+	ten, _ := wide.Column("10")
+	assert.DeepEqual(t, []interface{}{nil, nil, 5.0}, values(ten))
+
+	first, err := df.Pivot("day", "event", "count", dataframe.AggFirst)
+	assert.NoError(t, err)
+	two, _ = first.Column("2")
+	assert.DeepEqual(t, []interface{}{1.0, nil, nil}, values(two))
+
+	_, err = df.Pivot("day", "missing", "count", dataframe.AggSum)
+	assert.Error(t, err)
+	_, err = df.Pivot("day", "event", "day", dataframe.AggMean)
+	assert.Error(t, err)
+
+	nullKeys := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("day", []string{"mon", "tue"}),
+		series.NewSeries("event", []interface{}{"Null", primitive.Null{}}),
+		series.NewSeries("count", []float64{1, 2}),
+	})
+	_, err = nullKeys.Pivot("day", "event", "count", dataframe.AggSum)
+	assert.Error(t, err)
+}
+
+func TestMelt(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("id", []string{"a", "b"}),
+		series.NewSeries("x", []int64{1, 2}),
+		series.NewSeries("y", []interface{}{3.5, primitive.Null{}}),
+	})
+
+	long, err := df.Melt([]string{"id"}, nil, "", "")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "variable", "value"}, long.GetColumnNames())
+	assert.Equal(t, 4, long.Height())
+
+	id, _ := long.Column("id")
+	assert.DeepEqual(t, []interface{}{"a", "b", "a", "b"}, values(id))
+	variable, _ := long.Column("variable")
+	assert.DeepEqual(t, []interface{}{"x", "x", "y", "y"}, values(variable))
+	value, _ := long.Column("value")
+	assert.Equal(t, arrow.FLOAT64, value.Type())
+	assert.DeepEqual(t, []interface{}{1.0, 2.0, 3.5, nil}, values(value))
+
+	long, err = df.Melt(nil, []string{"id", "x"}, "key", "val")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"key", "val"}, long.GetColumnNames())
+	value, _ = long.Column("val")
+	assert.DeepEqual(t, []interface{}{"a", "b", "1", "2"}, values(value))
+
+	_, err = df.Melt([]string{"missing"}, nil, "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/kstremick/mango/core/series"
+)
+
+// Filter keeps the rows where the mask is true.
+// Null values in the mask are treated as false.
+func (df *DataFrame) Filter(mask *series.SeriesT[bool]) (*DataFrame, error) {
+	out := make([]series.Series, len(df.Series))
+	for i, s := range df.Series {
+		filtered, err := s.Filter(mask)
+		if err != nil {
+			releaseColumns(out[:i])
+			return nil, err
+		}
+		out[i] = filtered
+	}
+	return NewDataFrame(out), nil
+}
+
+// Take keeps the rows at the given indices, in the given order.
+func (df *DataFrame) Take(indices *series.SeriesT[int64]) (*DataFrame, error) {
+	out := make([]series.Series, len(df.Series))
+	for i, s := range df.Series {
+		taken, err := s.Take(indices)
+		if err != nil {
+			releaseColumns(out[:i])
+			return nil, err
+		}
+		out[i] = taken
+	}
+	return NewDataFrame(out), nil
+}
+
+// Slice returns a zero-copy slice of the rows of the DataFrame.
+// When offset is negative the offset is counted from the end of the DataFrame.
+func (df *DataFrame) Slice(offset, length int64) (*DataFrame, error) {
+	out := make([]series.Series, len(df.Series))
+	for i, s := range df.Series {
+		sliced, err := s.Slice(offset, length)
+		if err != nil {
+			releaseColumns(out[:i])
+			return nil, err
+		}
+		out[i] = sliced
+	}
+	return NewDataFrame(out), nil
+}
+
+// Head returns the first n rows of the DataFrame, or all of them if there are fewer than n.
+func (df *DataFrame) Head(n int) *DataFrame {
+	n = clamp(n, df.Height())
+	// The bounds are already checked, so this cannot fail.
+	ret, _ := df.Slice(0, int64(n))
+	return ret
+}
+
+// Tail returns the last n rows of the DataFrame, or all of them if there are fewer than n.
+func (df *DataFrame) Tail(n int) *DataFrame {
+	n = clamp(n, df.Height())
+	// The bounds are already checked, so this cannot fail.
+	ret, _ := df.Slice(int64(df.Height()-n), int64(n))
+	return ret
+}
+
+// Sample returns n rows picked at random, using seed for reproducibility.
+// Without replacement, every row is picked at most once, so n must not exceed the height.
+func (df *DataFrame) Sample(n int, withReplacement bool, seed int64) (*DataFrame, error) {
+	height := df.Height()
+	if n < 0 {
+		return nil, fmt.Errorf("cannot sample a negative number of rows %d", n)
+	}
+	if !withReplacement && n > height {
+		return nil, fmt.Errorf("cannot sample %d rows from a DataFrame of height %d without replacement", n, height)
+	}
+	if withReplacement && n > 0 && height == 0 {
+		return nil, fmt.Errorf("cannot sample %d rows from an empty DataFrame", n)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	indices := make([]int64, n)
+	if withReplacement {
+		for i := range indices {
+			indices[i] = rng.Int63n(int64(height))
+		}
+	} else {
+		for i, row := range rng.Perm(height)[:n] {
+			indices[i] = int64(row)
+		}
+	}
+	taken := series.NewSeriesTFromTSlice("", indices, nil)
+	defer taken.Release()
+	return df.Take(&taken)
+}
+
+// SampleFrac returns a fraction of the rows picked at random, using seed for reproducibility.
+// The number of rows is rounded to the nearest integer.
+func (df *DataFrame) SampleFrac(fraction float64, withReplacement bool, seed int64) (*DataFrame, error) {
+	if fraction < 0 {
+		return nil, fmt.Errorf("cannot sample a negative fraction of rows %v", fraction)
+	}
+	return df.Sample(int(math.Round(fraction*float64(df.Height()))), withReplacement, seed)
+}
+
+// Shuffle returns the rows of the DataFrame in a random order, using seed for reproducibility.
+func (df *DataFrame) Shuffle(seed int64) (*DataFrame, error) {
+	return df.Sample(df.Height(), false, seed)
+}
+
+// clamp restricts n to [0, max].
+func clamp(n, max int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
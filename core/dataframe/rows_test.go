@@ -0,0 +1,95 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func testRowsDataFrame() *dataframe.DataFrame {
+	return dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []int64{1, 2, 3, 4, 5}),
+		series.NewSeries("y", []string{"a", "b", "c", "d", "e"}),
+	})
+}
+
+func TestFilter(t *testing.T) {
+	df := testRowsDataFrame()
+	mask := series.NewSeriesTFromTSlice("", []bool{true, false, true, false, false}, []bool{true, true, true, true, false})
+
+	filtered, err := df.Filter(&mask)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, filtered.Height())
+	y, _ := filtered.Column("y")
+	assert.DeepEqual(t, []interface{}{"a", "c"}, values(y))
+
+	none := series.NewSeriesTFromTSlice("", []bool{false, false, false, false, false}, nil)
+	filtered, err = df.Filter(&none)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, filtered.Height())
+
+	short := series.NewSeriesTFromTSlice("", []bool{true}, nil)
+	_, err = df.Filter(&short)
+	assert.Error(t, err)
+}
+
+func TestSliceHeadTail(t *testing.T) {
+	df := testRowsDataFrame()
+
+	sliced, err := df.Slice(1, 2)
+	assert.NoError(t, err)
+	x, _ := sliced.Column("x")
+	assert.DeepEqual(t, []interface{}{int64(2), int64(3)}, values(x))
+
+	_, err = df.Slice(4, 2)
+	assert.Error(t, err)
+
+	x, _ = df.Head(2).Column("x")
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2)}, values(x))
+	x, _ = df.Tail(2).Column("x")
+	assert.DeepEqual(t, []interface{}{int64(4), int64(5)}, values(x))
+	assert.Equal(t, 5, df.Head(10).Height())
+	assert.Equal(t, 5, df.Tail(10).Height())
+	assert.Equal(t, 0, df.Tail(-1).Height())
+}
+
+func TestSample(t *testing.T) {
+	df := testRowsDataFrame()
+
+	sampled, err := df.Sample(3, false, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sampled.Height())
+	again, err := df.Sample(3, false, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, sampled.String(), again.String())
+
+	x, _ := sampled.Column("x")
+	y, _ := sampled.Column("y")
+	seen := make(map[int64]bool)
+	for i := 0; i < sampled.Height(); i++ {
+		v := x.ValueExn(i).Value.(int64)
+		assert.False(t, seen[v])
+		seen[v] = true
+		assert.Equal(t, string(rune('a'+v-1)), y.ValueExn(i).Value)
+	}
+
+	_, err = df.Sample(6, false, 42)
+	assert.Error(t, err)
+	sampled, err = df.Sample(6, true, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sampled.Height())
+
+	sampled, err = df.SampleFrac(0.4, false, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sampled.Height())
+
+	shuffled, err := df.Shuffle(7)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, shuffled.Height())
+	x, _ = shuffled.Column("x")
+	sum, _ := x.Sum()
+	assert.Equal(t, 15.0, sum)
+}
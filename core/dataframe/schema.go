@@ -0,0 +1,137 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+// Width returns the number of columns in the DataFrame
+func (df *DataFrame) Width() int {
+	return len(df.Series)
+}
+
+// Shape returns the number of rows and columns in the DataFrame
+func (df *DataFrame) Shape() (int, int) {
+	return df.Height(), df.Width()
+}
+
+// Dtypes returns the arrow data types of the columns, in order
+func (df *DataFrame) Dtypes() []arrow.DataType {
+	dtypes := make([]arrow.DataType, len(df.Series))
+	for i, s := range df.Series {
+		dtypes[i] = s.DataType()
+	}
+	return dtypes
+}
+
+// Schema returns the names and arrow data types of the columns, in order.
+// Every field is nullable.
+func (df *DataFrame) Schema() *arrow.Schema {
+	fields := make([]arrow.Field, len(df.Series))
+	for i, s := range df.Series {
+		fields[i] = arrow.Field{Name: s.Name, Type: s.DataType(), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// Drop returns a new DataFrame without the given columns.
+func (df *DataFrame) Drop(colNames ...string) (*DataFrame, error) {
+	drop := make(map[string]bool, len(colNames))
+	for _, name := range colNames {
+		if _, err := df.Column(name); err != nil {
+			return nil, err
+		}
+		drop[name] = true
+	}
+	out := make([]series.Series, 0, len(df.Series))
+	for _, s := range df.Series {
+		if !drop[s.Name] {
+			s.Retain()
+			out = append(out, s)
+		}
+	}
+	return NewDataFrame(out), nil
+}
+
+// Rename returns a new DataFrame with columns renamed from the keys of mapping to its values.
+// Returns an error if a column is not found, or if the new names are not unique.
+func (df *DataFrame) Rename(mapping map[string]string) (*DataFrame, error) {
+	for name := range mapping {
+		if _, err := df.Column(name); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, len(df.Series))
+	seen := make(map[string]bool, len(df.Series))
+	for i, s := range df.Series {
+		name := s.Name
+		if newName, ok := mapping[name]; ok {
+			name = newName
+		}
+		if seen[name] {
+			return nil, errors.New("duplicate column: " + name)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	out := make([]series.Series, len(df.Series))
+	for i, s := range df.Series {
+		s.Retain()
+		out[i] = s.Alias(names[i])
+	}
+	return NewDataFrame(out), nil
+}
+
+// Reorder returns a new DataFrame with the given columns first, in the given order,
+// followed by the remaining columns in their current order.
+func (df *DataFrame) Reorder(colNames ...string) (*DataFrame, error) {
+	seen := make(map[string]bool, len(colNames))
+	for _, name := range colNames {
+		if seen[name] {
+			return nil, errors.New("duplicate column: " + name)
+		}
+		seen[name] = true
+	}
+	first, err := df.Select(colNames...)
+	if err != nil {
+		return nil, err
+	}
+	out := first.Series
+	for _, s := range df.Series {
+		if !seen[s.Name] {
+			s.Retain()
+			out = append(out, s)
+		}
+	}
+	return NewDataFrame(out), nil
+}
+
+// CastColumns returns a new DataFrame with columns cast from their current type
+// to the type given for their name in dtypes.
+func (df *DataFrame) CastColumns(dtypes map[string]arrow.DataType) (*DataFrame, error) {
+	for name := range dtypes {
+		if _, err := df.Column(name); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]series.Series, len(df.Series))
+	for i, s := range df.Series {
+		dtype, ok := dtypes[s.Name]
+		if !ok {
+			s.Retain()
+			out[i] = s
+			continue
+		}
+		casted, err := s.Cast(dtype)
+		if err != nil {
+			releaseColumns(out[:i])
+			return nil, fmt.Errorf("column %s: %w", s.Name, err)
+		}
+		out[i] = casted
+	}
+	return NewDataFrame(out), nil
+}
@@ -0,0 +1,76 @@
+package dataframe_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func testSchemaDataFrame() *dataframe.DataFrame {
+	return dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []int64{1, 2}),
+		series.NewSeries("b", []string{"x", "y"}),
+		series.NewSeries("c", []float64{1.5, 2.5}),
+	})
+}
+
+func TestSchema(t *testing.T) {
+	df := testSchemaDataFrame()
+
+	assert.Equal(t, 3, df.Width())
+	height, width := df.Shape()
+	assert.Equal(t, 2, height)
+	assert.Equal(t, 3, width)
+	assert.DeepEqual(t, []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64}, df.Dtypes())
+
+	schema := df.Schema()
+	assert.Equal(t, 3, len(schema.Fields()))
+	assert.Equal(t, "b", schema.Field(1).Name)
+	assert.Equal(t, arrow.STRING, schema.Field(1).Type.ID())
+}
+
+func TestDropRenameReorder(t *testing.T) {
+	df := testSchemaDataFrame()
+
+	dropped, err := df.Drop("b")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"a", "c"}, dropped.GetColumnNames())
+	assert.DeepEqual(t, []string{"a", "b", "c"}, df.GetColumnNames())
+	_, err = df.Drop("z")
+	assert.Error(t, err)
+
+	renamed, err := df.Rename(map[string]string{"a": "id", "c": "score"})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "b", "score"}, renamed.GetColumnNames())
+	assert.DeepEqual(t, []string{"a", "b", "c"}, df.GetColumnNames())
+	_, err = df.Rename(map[string]string{"a": "b"})
+	assert.Error(t, err)
+	_, err = df.Rename(map[string]string{"z": "y"})
+	assert.Error(t, err)
+
+	reordered, err := df.Reorder("c", "a")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"c", "a", "b"}, reordered.GetColumnNames())
+	_, err = df.Reorder("a", "a")
+	assert.Error(t, err)
+	_, err = df.Reorder("z")
+	assert.Error(t, err)
+}
+
+func TestCastColumns(t *testing.T) {
+	df := testSchemaDataFrame()
+
+	casted, err := df.CastColumns(map[string]arrow.DataType{"a": arrow.PrimitiveTypes.Float64})
+	assert.NoError(t, err)
+	assert.Equal(t, arrow.FLOAT64, casted.Dtypes()[0].ID())
+	assert.Equal(t, arrow.INT64, df.Dtypes()[0].ID())
+
+	_, err = df.CastColumns(map[string]arrow.DataType{"b": arrow.PrimitiveTypes.Int64})
+	assert.Error(t, err)
+	_, err = df.CastColumns(map[string]arrow.DataType{"z": arrow.PrimitiveTypes.Int64})
+	assert.Error(t, err)
+}
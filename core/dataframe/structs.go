@@ -0,0 +1,307 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structField is a field of a struct that maps to a column.
+type structField struct {
+	name string
+	// index is the path of the field through embedded structs, as used by reflect.Value.FieldByIndex.
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+	depth     int
+}
+
+// FromStructs creates a new DataFrame from a slice of structs, or of pointers to structs.
+// Every exported field becomes a column, named after the field or its `mango:"name"` tag.
+// A field tagged `mango:"-"` is skipped, and the fields of embedded structs are flattened.
+// Integer fields become int64 columns, floating point fields float64, and time.Time fields timestamps in microseconds.
+// Pointer fields are nullable, where nil pointers are nulls,
+// and the zero values of fields tagged with omitempty, as in `mango:"name,omitempty"`, are nulls too.
+func FromStructs(slice any) (*DataFrame, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a slice of structs, got %T", slice)
+	}
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	fields, err := structFields(elemType)
+	if err != nil {
+		return nil, err
+	}
+
+	mem := series.DefaultAllocator()
+	cols := make([]series.Series, len(fields))
+	for i, f := range fields {
+		dtype, err := structFieldDataType(f.typ)
+		if err != nil {
+			releaseColumns(cols[:i])
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+		b := array.NewBuilder(mem, dtype)
+		defer b.Release()
+		for row := 0; row < v.Len(); row++ {
+			elem := v.Index(row)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					releaseColumns(cols[:i])
+					return nil, fmt.Errorf("element %d is nil", row)
+				}
+				elem = elem.Elem()
+			}
+			// The field is null when an embedded pointer on its path is nil.
+			fv, err := elem.FieldByIndexErr(f.index)
+			if err != nil || (f.omitEmpty && fv.IsZero()) {
+				b.AppendNull()
+				continue
+			}
+			if err := appendStructValue(b, fv); err != nil {
+				releaseColumns(cols[:i])
+				return nil, fmt.Errorf("field %s, element %d: %w", f.name, row, err)
+			}
+		}
+		arr := b.NewArray()
+		cols[i] = series.NewSeriesFromArray(f.name, arr)
+		arr.Release()
+	}
+	return newDataFrameOrRelease(cols)
+}
+
+// ToStructs stores the rows of the DataFrame into the slice pointed to by dst,
+// which must be a pointer to a slice of structs, or of pointers to structs.
+// Columns are matched to fields as in FromStructs. Columns without a field are ignored,
+// and fields without a column are left to their zero value.
+// Nulls become nil pointers, or zero values for fields tagged with omitempty,
+// and are an error for any other field.
+func (df *DataFrame) ToStructs(dst any) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice of structs, got %T", dst)
+	}
+	sliceType := ptr.Elem().Type()
+	elemType := sliceType.Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+	fields, err := structFields(elemType)
+	if err != nil {
+		return err
+	}
+
+	out := reflect.MakeSlice(sliceType, df.Height(), df.Height())
+	if isPointer {
+		for row := 0; row < df.Height(); row++ {
+			out.Index(row).Set(reflect.New(elemType))
+		}
+	}
+	for _, f := range fields {
+		col, err := df.Column(f.name)
+		if err != nil {
+			continue
+		}
+		for row := 0; row < df.Height(); row++ {
+			elem := out.Index(row)
+			if isPointer {
+				elem = elem.Elem()
+			}
+			if err := setStructField(elem, f, col.ValueExn(row).Value, col.IsValidExn(row)); err != nil {
+				return fmt.Errorf("column %s, row %d: %w", f.name, row, err)
+			}
+		}
+	}
+	ptr.Elem().Set(out)
+	return nil
+}
+
+// structFields returns the fields of a struct type that map to columns, in the order of their declaration.
+// As with encoding/json, a field shadows the fields of the same name that are embedded deeper.
+func structFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s", t)
+	}
+	var fields []structField
+	var collect func(t reflect.Type, index []int)
+	collect = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("mango")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			path := append(append([]int{}, index...), i)
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+				// Like encoding/json, ignore unexported embedded pointers, which cannot be allocated.
+				if !sf.IsExported() && sf.Type.Kind() == reflect.Pointer {
+					continue
+				}
+				collect(ft, path)
+				continue
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fields = append(fields, structField{
+				name:      name,
+				index:     path,
+				typ:       sf.Type,
+				omitEmpty: opts == "omitempty",
+				depth:     len(index),
+			})
+		}
+	}
+	collect(t, nil)
+
+	depths := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if d, ok := depths[f.name]; !ok || f.depth < d {
+			depths[f.name] = f.depth
+		} else if f.depth == d {
+			return nil, fmt.Errorf("duplicate field name %s in %s", f.name, t)
+		}
+	}
+	ret := fields[:0]
+	for _, f := range fields {
+		if f.depth == depths[f.name] {
+			ret = append(ret, f)
+		}
+	}
+	return ret, nil
+}
+
+// structFieldDataType returns the arrow datatype of the column of a field of type t.
+func structFieldDataType(t reflect.Type) (arrow.DataType, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case reflect.Float32, reflect.Float64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case reflect.String:
+		return arrow.BinaryTypes.String, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// appendStructValue appends the value of a field to b, whose type is given by structFieldDataType.
+func appendStructValue(b array.Builder, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			b.AppendNull()
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		if v.CanInt() {
+			b.Append(v.Int())
+		} else if v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("%d overflows int64", v.Uint())
+		} else {
+			b.Append(int64(v.Uint()))
+		}
+	case *array.Float64Builder:
+		b.Append(v.Float())
+	case *array.BooleanBuilder:
+		b.Append(v.Bool())
+	case *array.StringBuilder:
+		b.Append(v.String())
+	case *array.TimestampBuilder:
+		b.Append(arrow.Timestamp(v.Interface().(time.Time).UnixMicro()))
+	}
+	return nil
+}
+
+// setStructField sets the field f of the struct elem to the value of a column.
+func setStructField(elem reflect.Value, f structField, val interface{}, valid bool) error {
+	if !valid {
+		if f.typ.Kind() != reflect.Pointer && !f.omitEmpty {
+			return fmt.Errorf("cannot store null in field of type %s", f.typ)
+		}
+		return nil
+	}
+
+	fv := elem
+	for _, i := range f.index {
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		fv = fv.Field(i)
+	}
+	if fv.Kind() == reflect.Pointer {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		fv = fv.Elem()
+	}
+
+	rv := reflect.ValueOf(val)
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !rv.CanInt() {
+			break
+		}
+		if fv.OverflowInt(rv.Int()) {
+			return fmt.Errorf("%d overflows %s", rv.Int(), fv.Type())
+		}
+		fv.SetInt(rv.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !rv.CanInt() {
+			break
+		}
+		if rv.Int() < 0 || fv.OverflowUint(uint64(rv.Int())) {
+			return fmt.Errorf("%d overflows %s", rv.Int(), fv.Type())
+		}
+		fv.SetUint(uint64(rv.Int()))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if rv.CanFloat() {
+			fv.SetFloat(rv.Float())
+			return nil
+		}
+		if rv.CanInt() {
+			fv.SetFloat(float64(rv.Int()))
+			return nil
+		}
+	default:
+		if rv.Kind() == fv.Kind() && rv.Type().ConvertibleTo(fv.Type()) {
+			fv.Set(rv.Convert(fv.Type()))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store %T in field of type %s", val, fv.Type())
+}
@@ -0,0 +1,113 @@
+package dataframe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kstremick/mango/core/dataframe"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+type structsBase struct {
+	ID      int64 `mango:"id"`
+	Created time.Time
+}
+
+type structsRow struct {
+	structsBase
+	Name    string   `mango:"name"`
+	Score   *float64 `mango:"score"`
+	Level   uint8    `mango:"level,omitempty"`
+	Active  bool
+	Ignored string `mango:"-"`
+	hidden  int
+}
+
+func TestFromStructs(t *testing.T) {
+	ts := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	score := 1.5
+	rows := []structsRow{
+		{structsBase{1, ts}, "a", &score, 3, true, "x", 1},
+		{structsBase{2, ts.Add(time.Hour)}, "b", nil, 0, false, "y", 2},
+	}
+	df, err := dataframe.FromStructs(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, df.GetColumnNames(), []string{"id", "Created", "name", "score", "level", "Active"})
+	assert.Equal(t, df.Series[1].DataType(), arrow.FixedWidthTypes.Timestamp_us)
+	assert.Equal(t, df.Series[4].DataType(), arrow.PrimitiveTypes.Int64)
+	assert.Equal(t, df.Series[1].ValueExn(1).Value, ts.Add(time.Hour))
+	assert.Equal(t, df.Series[3].IsNull(), []bool{false, true})
+	assert.Equal(t, df.Series[4].IsNull(), []bool{false, true})
+
+	var got []structsRow
+	assert.NoError(t, df.ToStructs(&got))
+	for i := range rows {
+		rows[i].Ignored, rows[i].hidden = "", 0
+	}
+	assert.Equal(t, got, rows)
+
+	var ptrs []*structsRow
+	assert.NoError(t, df.ToStructs(&ptrs))
+	assert.Equal(t, *ptrs[1], rows[1])
+
+	fromPtrs, err := dataframe.FromStructs(ptrs)
+	assert.NoError(t, err)
+	assert.Equal(t, fromPtrs.String(), df.String())
+}
+
+func TestStructsErrors(t *testing.T) {
+	type duplicate struct {
+		A int `mango:"x"`
+		B int `mango:"x"`
+	}
+	type unsupported struct {
+		A []int
+	}
+	type narrow struct {
+		ID int8 `mango:"id"`
+	}
+	type notNull struct {
+		Score float64 `mango:"score"`
+	}
+	type testCase struct {
+		name string
+		fn   func() error
+	}
+	df, err := dataframe.FromStructs([]structsRow{{Name: "a"}, {Name: "b"}})
+	assert.NoError(t, err)
+	big, err := dataframe.FromStructs([]structsRow{{structsBase: structsBase{ID: 1000}}})
+	assert.NoError(t, err)
+	testCases := []testCase{
+		{"not a slice", func() error { _, err := dataframe.FromStructs(structsRow{}); return err }},
+		{"not structs", func() error { _, err := dataframe.FromStructs([]int{1}); return err }},
+		{"duplicate names", func() error { _, err := dataframe.FromStructs([]duplicate{}); return err }},
+		{"unsupported type", func() error { _, err := dataframe.FromStructs([]unsupported{}); return err }},
+		{"nil element", func() error { _, err := dataframe.FromStructs([]*structsRow{nil}); return err }},
+		{"not a pointer", func() error { return df.ToStructs([]structsRow{}) }},
+		{"null in non-pointer field", func() error { return df.ToStructs(&[]notNull{}) }},
+		{"overflow", func() error { return big.ToStructs(&[]narrow{}) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, tc.fn())
+		})
+	}
+}
+
+func TestStructsShadowing(t *testing.T) {
+	type Inner struct {
+		Name string
+		Kind string
+	}
+	type outer struct {
+		*Inner
+		Name string
+	}
+	df, err := dataframe.FromStructs([]outer{{&Inner{"in", "k"}, "out"}, {nil, "out2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, df.GetColumnNames(), []string{"Kind", "Name"})
+	assert.Equal(t, df.Series[0].IsNull(), []bool{false, true})
+	assert.Equal(t, df.Series[1].ValueExn(0).Value, "out")
+}
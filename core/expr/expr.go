@@ -0,0 +1,231 @@
+// Package expr provides expressions over the columns of a DataFrame.
+// Inspired by https://pola-rs.github.io/polars/user-guide/expressions/
+package expr
+
+import (
+	"sort"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+)
+
+// Expr is an expression over the columns of a DataFrame.
+// Expressions are built by chaining methods on Col, and are only computed by Evaluate.
+type Expr struct {
+	name  string
+	input string
+	fn    func(s series.Series) (series.Series, error)
+
+	// partitionBy and orderBy are only set for window expressions.
+	partitionBy []string
+	orderBy     []string
+}
+
+// Col returns an expression selecting the column with the given name.
+func Col(name string) Expr {
+	return Expr{
+		name:  name,
+		input: name,
+		fn: func(s series.Series) (series.Series, error) {
+			// The column is shared with the DataFrame, so the result holds its own reference.
+			s.Retain()
+			return s, nil
+		},
+	}
+}
+
+// Name returns the name of the Series produced by the expression.
+func (e Expr) Name() string {
+	return e.name
+}
+
+// Alias renames the Series produced by the expression.
+func (e Expr) Alias(name string) Expr {
+	e.name = name
+	return e
+}
+
+// Evaluate computes the expression over the DataFrame.
+// Aggregations produce a Series with a single value, unless they are windowed with Over,
+// in which case the aggregated value of each partition is broadcast to its rows.
+func (e Expr) Evaluate(df *dataframe.DataFrame) (series.Series, error) {
+	input, err := df.Column(e.input)
+	if err != nil {
+		return series.Series{}, err
+	}
+	var ret series.Series
+	if e.partitionBy == nil && e.orderBy == nil {
+		ret, err = e.fn(input)
+	} else {
+		ret, err = e.evaluateWindow(df, input)
+	}
+	if err != nil {
+		return series.Series{}, err
+	}
+	return ret.Alias(e.name), nil
+}
+
+// then returns a copy of the expression that applies fn to its result.
+// The result is released once fn returned, so fn must retain it to return it.
+func (e Expr) then(fn func(s series.Series) (series.Series, error)) Expr {
+	prev := e.fn
+	e.fn = func(s series.Series) (series.Series, error) {
+		s, err := prev(s)
+		if err != nil {
+			return series.Series{}, err
+		}
+		defer s.Release()
+		return fn(s)
+	}
+	return e
+}
+
+// Sum aggregates the expression into its sum.
+func (e Expr) Sum() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		sum, err := s.Sum()
+		if err != nil {
+			return series.Series{}, err
+		}
+		return float64Series(s.Name, primitive.Some(sum)), nil
+	})
+}
+
+// Mean aggregates the expression into its mean.
+func (e Expr) Mean() Expr {
+	return e.aggregate((*series.Series).Mean)
+}
+
+// Min aggregates the expression into its minimum.
+func (e Expr) Min() Expr {
+	return e.aggregate((*series.Series).Min)
+}
+
+// Max aggregates the expression into its maximum.
+func (e Expr) Max() Expr {
+	return e.aggregate((*series.Series).Max)
+}
+
+// Std aggregates the expression into its sample standard deviation.
+func (e Expr) Std() Expr {
+	return e.aggregate((*series.Series).Std)
+}
+
+// Var aggregates the expression into its sample variance.
+func (e Expr) Var() Expr {
+	return e.aggregate((*series.Series).Var)
+}
+
+// Median aggregates the expression into its median.
+func (e Expr) Median() Expr {
+	return e.aggregate((*series.Series).Median)
+}
+
+// Count aggregates the expression into its number of non-null values.
+func (e Expr) Count() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return series.NewSeries(s.Name, []int64{int64(s.Len() - s.NullN())}), nil
+	})
+}
+
+// First aggregates the expression into its first value.
+func (e Expr) First() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.Slice(0, 1)
+	})
+}
+
+// Last aggregates the expression into its last value.
+func (e Expr) Last() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.Slice(-1, 1)
+	})
+}
+
+// CumSum computes the cumulative sum of the expression.
+func (e Expr) CumSum() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.CumSum(false)
+	})
+}
+
+// CumCount computes the cumulative count of non-null values of the expression.
+func (e Expr) CumCount() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.CumCount(false), nil
+	})
+}
+
+// Shift shifts the expression by n positions, filling with nulls.
+func (e Expr) Shift(n int) Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.Shift(n, primitive.None[interface{}]())
+	})
+}
+
+// Diff computes the difference between each value of the expression and the value n positions before it.
+func (e Expr) Diff(n int) Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		return s.Diff(n)
+	})
+}
+
+// RowNumber numbers the rows of the expression, starting at 1.
+func (e Expr) RowNumber() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		rets := make([]int64, s.Len())
+		for i := range rets {
+			rets[i] = int64(i + 1)
+		}
+		return series.NewSeries(s.Name, rets), nil
+	})
+}
+
+// Rank ranks the values of the expression in ascending order, starting at 1.
+// Equal values get the same rank, leaving gaps after them like SQL's RANK().
+// Null values get a null rank.
+func (e Expr) Rank() Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		vals := make([]primitive.Optional[interface{}], s.Len())
+		order := make([]int, s.Len())
+		for i := range vals {
+			vals[i] = s.ValueExn(i)
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return primitive.Compare(vals[order[a]], vals[order[b]]) < 0
+		})
+
+		ranks := make([]int64, s.Len())
+		valids := make([]bool, s.Len())
+		for pos, i := range order {
+			if !vals[i].Valid {
+				continue
+			}
+			valids[i] = true
+			if pos > 0 && primitive.Compare(vals[order[pos-1]], vals[i]) == 0 {
+				ranks[i] = ranks[order[pos-1]]
+			} else {
+				ranks[i] = int64(pos + 1)
+			}
+		}
+		return series.NewSeriesTFromTSlice(s.Name, ranks, valids).Series, nil
+	})
+}
+
+// aggregate returns a copy of the expression aggregated into a single float64 with fn.
+func (e Expr) aggregate(fn func(s *series.Series) (primitive.Optional[float64], error)) Expr {
+	return e.then(func(s series.Series) (series.Series, error) {
+		v, err := fn(&s)
+		if err != nil {
+			return series.Series{}, err
+		}
+		return float64Series(s.Name, v), nil
+	})
+}
+
+// float64Series creates a float64 Series holding the single value v.
+func float64Series(name string, v primitive.Optional[float64]) series.Series {
+	return series.NewSeriesTFromTSlice(name, []float64{v.Value}, []bool{v.Valid}).Series
+}
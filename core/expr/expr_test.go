@@ -0,0 +1,109 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/expr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func testDataFrame() *dataframe.DataFrame {
+	return dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("PassengerId", []int64{1, 2, 3, 4, 5}),
+		series.NewSeries("Pclass", []int64{3, 1, 3, 1, 2}),
+		series.NewSeries("Fare", []interface{}{7.0, 70.0, 9.0, 50.0, primitive.Null{}}),
+	})
+}
+
+// values returns the values of a Series, with nulls as nil.
+func values(s series.Series) []interface{} {
+	ret := make([]interface{}, s.Len())
+	for i := range ret {
+		v := s.ValueExn(i)
+		if v.Valid {
+			ret[i] = v.Value
+		}
+	}
+	return ret
+}
+
+func TestAggregations(t *testing.T) {
+	df := testDataFrame()
+
+	mean, err := expr.Col("Fare").Mean().Evaluate(df)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fare", mean.Name)
+	assert.DeepEqual(t, []interface{}{34.0}, values(mean))
+
+	count, err := expr.Col("Fare").Count().Alias("n").Evaluate(df)
+	assert.NoError(t, err)
+	assert.Equal(t, "n", count.Name)
+	assert.DeepEqual(t, []interface{}{int64(4)}, values(count))
+
+	_, err = expr.Col("Age").Mean().Evaluate(df)
+	assert.Error(t, err)
+}
+
+func TestOver(t *testing.T) {
+	df := testDataFrame()
+
+	mean, err := expr.Col("Fare").Mean().Over("Pclass").Alias("MeanFare").Evaluate(df)
+	assert.NoError(t, err)
+	assert.Equal(t, "MeanFare", mean.Name)
+	assert.Equal(t, arrow.FLOAT64, mean.Type())
+	assert.DeepEqual(t, []interface{}{8.0, 60.0, 8.0, 60.0, nil}, values(mean))
+
+	first, err := expr.Col("PassengerId").First().Over("Pclass").Evaluate(df)
+	assert.NoError(t, err)
+	assert.Equal(t, arrow.INT64, first.Type())
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(1), int64(2), int64(5)}, values(first))
+
+	_, err = expr.Col("Fare").Mean().Over("Missing").Evaluate(df)
+	assert.Error(t, err)
+}
+
+func TestOverDistinctKeys(t *testing.T) {
+	// The values of both rows would read "x\x00true:y\x00true:z" if they were joined naively.
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("a", []interface{}{"x\x00true:y", "x", "", primitive.Null{}}),
+		series.NewSeries("b", []string{"z", "y\x00true:z", "w", "w"}),
+		series.NewSeries("v", []int64{1, 2, 3, 4}),
+	})
+	count, err := expr.Col("v").Count().Over("a", "b").Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(1), int64(1), int64(1), int64(1)}, values(count))
+}
+
+func TestOverOrderBy(t *testing.T) {
+	df := testDataFrame()
+
+	rowNumber, err := expr.Col("PassengerId").RowNumber().Over("Pclass").OrderBy("Fare").Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(2), int64(1), int64(1)}, values(rowNumber))
+
+	cumSum, err := expr.Col("Fare").CumSum().Over("Pclass").OrderBy("PassengerId").Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{7.0, 70.0, 16.0, 120.0, nil}, values(cumSum))
+
+	rank, err := expr.Col("Fare").Rank().Over("Pclass").Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(2), int64(1), nil}, values(rank))
+
+	lag, err := expr.Col("PassengerId").Shift(1).OrderBy("Fare").Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{nil, int64(4), int64(1), int64(3), int64(2)}, values(lag))
+}
+
+func TestRank(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("x", []string{"b", "a", "b", "c"}),
+	})
+	rank, err := expr.Col("x").Rank().Evaluate(df)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(2), int64(1), int64(2), int64(4)}, values(rank))
+}
@@ -0,0 +1,87 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/expr"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/memtest"
+
+	"github.com/zeebo/assert"
+)
+
+func TestRelease(t *testing.T) {
+	memtest.CheckedAllocator(t)
+
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("g", []string{"a", "b", "a", "b", "a"}),
+		series.NewSeries("v", []float64{1, 2, 3, 4, 5}),
+		series.NewSeries("o", []int64{5, 4, 3, 2, 1}),
+	})
+	defer df.Release()
+
+	evaluate := func(e expr.Expr) func() (series.Series, error) {
+		return func() (series.Series, error) { return e.Evaluate(df) }
+	}
+	cases := []memtest.Case[series.Series]{
+		{Name: "column", Fn: evaluate(expr.Col("v"))},
+		{Name: "sum", Fn: evaluate(expr.Col("v").Sum())},
+		{Name: "mean", Fn: evaluate(expr.Col("v").Mean())},
+		{Name: "count", Fn: evaluate(expr.Col("v").Count())},
+		{Name: "first", Fn: evaluate(expr.Col("v").First())},
+		{Name: "last", Fn: evaluate(expr.Col("v").Last())},
+		{Name: "cum sum", Fn: evaluate(expr.Col("v").CumSum())},
+		{Name: "cum count", Fn: evaluate(expr.Col("v").CumCount())},
+		{Name: "shift", Fn: evaluate(expr.Col("v").Shift(1))},
+		{Name: "diff", Fn: evaluate(expr.Col("v").Diff(1))},
+		{Name: "row number", Fn: evaluate(expr.Col("v").RowNumber())},
+		{Name: "rank", Fn: evaluate(expr.Col("v").Rank())},
+		{Name: "sum of cum sum", Fn: evaluate(expr.Col("v").CumSum().Sum())},
+		{Name: "mean over", Fn: evaluate(expr.Col("v").Mean().Over("g"))},
+		{Name: "cum sum over ordered", Fn: evaluate(expr.Col("v").CumSum().Over("g").OrderBy("o"))},
+		{Name: "row number ordered", Fn: evaluate(expr.Col("v").RowNumber().OrderBy("o"))},
+	}
+	memtest.Run(t, cases, func(s series.Series) { s.Release() })
+}
+
+func TestReleaseParallel(t *testing.T) {
+	memtest.CheckedAllocator(t)
+
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("g", []string{"a", "b", "a"}),
+		series.NewSeries("v", []float64{1, 2, 3}),
+	})
+	defer df.Release()
+
+	memtest.Run(t, []memtest.Case[*dataframe.DataFrame]{
+		{Name: "select", Fn: func() (*dataframe.DataFrame, error) {
+			return expr.Select(df, expr.Col("v").CumSum(), expr.Col("g").Alias("h"))
+		}},
+		{Name: "with columns", Fn: func() (*dataframe.DataFrame, error) {
+			return expr.WithColumns(df, expr.Col("v").CumSum(), expr.Col("v").Alias("w"))
+		}},
+	}, func(df *dataframe.DataFrame) { df.Release() })
+
+	type testCase struct {
+		name string
+		fn   func() (*dataframe.DataFrame, error)
+	}
+	testCases := []testCase{
+		{"failing expression", func() (*dataframe.DataFrame, error) {
+			return expr.Select(df, expr.Col("v").CumSum(), expr.Col("v").Sum(), expr.Col("g").Sum())
+		}},
+		{"select of different lengths", func() (*dataframe.DataFrame, error) {
+			return expr.Select(df, expr.Col("v").CumSum(), expr.Col("v").Sum())
+		}},
+		{"with columns of different lengths", func() (*dataframe.DataFrame, error) {
+			return expr.WithColumns(df, expr.Col("v").CumSum(), expr.Col("v").Sum())
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.fn()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/internal/parallel"
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/series"
+)
+
+// Select evaluates the expressions over the DataFrame, returning a DataFrame of their results.
+// The expressions are evaluated in parallel, see SelectContext.
+func Select(df *dataframe.DataFrame, exprs ...Expr) (*dataframe.DataFrame, error) {
+	return SelectContext(context.Background(), df, series.ParallelOptions{}, exprs...)
+}
+
+// SelectContext is like Select, evaluating the expressions from several goroutines.
+// The columns keep the order of the expressions, and if several expressions fail,
+// the returned error is the one of the first failing expression.
+// If ctx is cancelled, SelectContext stops and returns the error of ctx.
+func SelectContext(ctx context.Context, df *dataframe.DataFrame, opts series.ParallelOptions, exprs ...Expr) (*dataframe.DataFrame, error) {
+	cols, err := evaluateAll(ctx, df, opts, exprs)
+	if err != nil {
+		return nil, err
+	}
+	out, err := dataframe.NewDataFrameChecked(cols)
+	if err != nil {
+		releaseAll(cols)
+		return nil, err
+	}
+	return out, nil
+}
+
+// WithColumns evaluates the expressions over the DataFrame, returning a new DataFrame with their results added.
+// Results replace the existing columns of the same name, as in DataFrame.WithColumns.
+// The expressions are evaluated in parallel, see SelectContext.
+func WithColumns(df *dataframe.DataFrame, exprs ...Expr) (*dataframe.DataFrame, error) {
+	return WithColumnsContext(context.Background(), df, series.ParallelOptions{}, exprs...)
+}
+
+// WithColumnsContext is like WithColumns, evaluating the expressions as in SelectContext.
+func WithColumnsContext(ctx context.Context, df *dataframe.DataFrame, opts series.ParallelOptions, exprs ...Expr) (*dataframe.DataFrame, error) {
+	cols, err := evaluateAll(ctx, df, opts, exprs)
+	if err != nil {
+		return nil, err
+	}
+	// WithColumns retains the added columns.
+	defer releaseAll(cols)
+	added := make([]*series.Series, len(cols))
+	for i := range cols {
+		if cols[i].Len() != df.Height() {
+			return nil, fmt.Errorf("expression %s: %w", exprs[i].Name(), &mangoerr.ShapeMismatchError{What: "length", Expected: df.Height(), Actual: cols[i].Len()})
+		}
+		added[i] = &cols[i]
+	}
+	return df.WithColumns(added...), nil
+}
+
+// evaluateAll evaluates every expression from its own task.
+// If any expression fails, the results of the others are released.
+func evaluateAll(ctx context.Context, df *dataframe.DataFrame, opts series.ParallelOptions, exprs []Expr) ([]series.Series, error) {
+	cols := make([]series.Series, len(exprs))
+	err := parallel.ForEach(ctx, len(exprs), opts.Workers, func(start, end int) func(int) error {
+		return func(i int) error {
+			s, err := exprs[i].Evaluate(df)
+			if err != nil {
+				return fmt.Errorf("expression %s: %w", exprs[i].Name(), err)
+			}
+			cols[i] = s
+			return nil
+		}
+	})
+	if err != nil {
+		releaseAll(cols)
+	}
+	// The index of the failing expression is already reported by its name.
+	if rowErr, ok := err.(*mangoerr.RowError); ok {
+		return nil, rowErr.Err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// releaseAll releases every Series of cols. Zero Series are ignored.
+func releaseAll(cols []series.Series) {
+	for i := range cols {
+		cols[i].Release()
+	}
+}
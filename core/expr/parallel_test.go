@@ -0,0 +1,54 @@
+package expr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kstremick/mango/core/expr"
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestSelect(t *testing.T) {
+	df := testDataFrame()
+
+	selected, err := expr.Select(df,
+		expr.Col("Fare").Mean().Over("Pclass").Alias("MeanFare"),
+		expr.Col("PassengerId"),
+		expr.Col("Fare").CumSum(),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MeanFare", "PassengerId", "Fare"}, selected.GetColumnNames())
+	assert.DeepEqual(t, []interface{}{8.0, 60.0, 8.0, 60.0, nil}, values(selected.Series[0]))
+
+	_, err = expr.SelectContext(context.Background(), df, series.ParallelOptions{Workers: 4},
+		expr.Col("PassengerId"),
+		expr.Col("Age"),
+		expr.Col("Name"),
+	)
+	assert.That(t, errors.Is(err, mangoerr.ErrColumnNotFound))
+	assert.Equal(t, "expression Age: column not found: Age", err.Error())
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = expr.SelectContext(cancelled, df, series.ParallelOptions{}, expr.Col("PassengerId"))
+	assert.That(t, errors.Is(err, context.Canceled))
+}
+
+func TestWithColumns(t *testing.T) {
+	df := testDataFrame()
+
+	added, err := expr.WithColumns(df,
+		expr.Col("Fare").CumSum(),
+		expr.Col("Fare").Mean().Over("Pclass").Alias("MeanFare"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PassengerId", "Pclass", "Fare", "MeanFare"}, added.GetColumnNames())
+	assert.DeepEqual(t, []interface{}{7.0, 77.0, 86.0, 136.0, nil}, values(added.Series[2]))
+
+	_, err = expr.WithColumns(df, expr.Col("Fare").Mean())
+	assert.That(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+}
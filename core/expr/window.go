@@ -0,0 +1,140 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/partition"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+// Over computes the expression separately within each partition of rows
+// that share the same values in the partitionBy columns, like pandas' groupby().transform.
+// The result is aligned with the rows of the DataFrame:
+// aggregations are broadcast to every row of their partition.
+// Over applies to the whole expression, so it should come after every other method but OrderBy and Alias.
+func (e Expr) Over(partitionBy ...string) Expr {
+	e.partitionBy = append([]string{}, partitionBy...)
+	return e
+}
+
+// OrderBy sorts the rows of each partition by the given columns before computing the expression.
+// This matters for order-dependent expressions like CumSum, RowNumber, Shift or First.
+// Without Over, the whole DataFrame is a single partition.
+// The result is still aligned with the original rows of the DataFrame.
+func (e Expr) OrderBy(cols ...string) Expr {
+	e.orderBy = append([]string{}, cols...)
+	return e
+}
+
+// evaluateWindow computes the expression within each partition,
+// and scatters the results back to the rows of the DataFrame.
+func (e Expr) evaluateWindow(df *dataframe.DataFrame, input series.Series) (series.Series, error) {
+	keys := make([]series.Series, len(e.partitionBy))
+	for i, name := range e.partitionBy {
+		col, err := df.Column(name)
+		if err != nil {
+			return series.Series{}, err
+		}
+		keys[i] = col
+	}
+	partitions := partition.Rows(keys, df.Height())
+	if len(partitions) == 0 {
+		return e.fn(input)
+	}
+	if err := sortPartitions(df, partitions, e.orderBy); err != nil {
+		return series.Series{}, err
+	}
+
+	var dtype arrow.DataType
+	var chunks []arrow.Array
+	defer func() {
+		for _, chunk := range chunks {
+			chunk.Release()
+		}
+	}()
+	positions := make([]int64, df.Height())
+	offset := 0
+	for _, rows := range partitions {
+		res, err := e.evaluatePartition(input, rows)
+		if err != nil {
+			return series.Series{}, err
+		}
+		if dtype == nil {
+			dtype = res.DataType()
+		} else if !arrow.TypeEqual(dtype, res.DataType()) {
+			res.Release()
+			return series.Series{}, fmt.Errorf("partitions have different types %s and %s", dtype, res.DataType())
+		}
+
+		switch res.Len() {
+		case 1:
+			for _, row := range rows {
+				positions[row] = int64(offset)
+			}
+		case len(rows):
+			for k, row := range rows {
+				positions[row] = int64(offset + k)
+			}
+		default:
+			res.Release()
+			return series.Series{}, fmt.Errorf("expression returned %d values for a partition of %d rows", res.Len(), len(rows))
+		}
+		for _, chunk := range res.Chunks() {
+			chunk.Retain()
+			chunks = append(chunks, chunk)
+		}
+		offset += res.Len()
+		res.Release()
+	}
+
+	results := series.NewSeriesFromChunked(e.name, arrow.NewChunked(dtype, chunks))
+	defer results.Release()
+	indices := series.NewSeriesTFromTSlice("", positions, nil)
+	defer indices.Release()
+	return results.Take(&indices)
+}
+
+// evaluatePartition computes the expression over the given rows of input.
+func (e Expr) evaluatePartition(input series.Series, rows []int64) (series.Series, error) {
+	indices := series.NewSeriesTFromTSlice("", rows, nil)
+	defer indices.Release()
+	part, err := input.Take(&indices)
+	if err != nil {
+		return series.Series{}, err
+	}
+	defer part.Release()
+	return e.fn(part)
+}
+
+// sortPartitions sorts the rows within each partition by the values of the given columns.
+// Ties keep their original order, and nulls sort last.
+func sortPartitions(df *dataframe.DataFrame, partitions [][]int64, cols []string) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	sortCols := make([]series.Series, len(cols))
+	for i, name := range cols {
+		col, err := df.Column(name)
+		if err != nil {
+			return err
+		}
+		sortCols[i] = col
+	}
+	for _, rows := range partitions {
+		sort.SliceStable(rows, func(a, b int) bool {
+			for _, col := range sortCols {
+				cmp := primitive.Compare(col.ValueExn(int(rows[a])), col.ValueExn(int(rows[b])))
+				if cmp != 0 {
+					return cmp < 0
+				}
+			}
+			return false
+		})
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package parallel runs row-wise work over several goroutines.
+package parallel
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kstremick/mango/core/mangoerr"
+)
+
+// tasksPerWorker is the number of ranges of rows given to each worker, to balance uneven work.
+const tasksPerWorker = 4
+
+// Workers returns the number of workers to use, defaulting to GOMAXPROCS when n is zero or less.
+func Workers(n int) int {
+	if n <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return n
+}
+
+// ForEach processes the rows [0, n) with the given number of workers.
+// The rows are split into contiguous ranges, and newTask is called once per range
+// to return the function processing each of its rows, in order.
+//
+// When a row fails, the rows after it are skipped, but the rows before it are still processed,
+// so the returned *mangoerr.RowError is always the one of the first failing row.
+// When ctx is cancelled, ForEach stops and returns the error of ctx,
+// unless every row was already processed.
+func ForEach(ctx context.Context, n, workers int, newTask func(start, end int) func(row int) error) error {
+	workers = Workers(workers)
+	size := (n + workers*tasksPerWorker - 1) / (workers * tasksPerWorker)
+	if size < 1 {
+		size = 1
+	}
+	var next atomic.Int64
+	// done counts the rows of the ranges processed to the end.
+	var done atomic.Int64
+	firstFailed := atomic.Int64{}
+	firstFailed.Store(math.MaxInt64)
+	var mu sync.Mutex
+	var rowErr *mangoerr.RowError
+
+	run := func() {
+		for {
+			start := int(next.Add(int64(size))) - size
+			if start >= n || int64(start) > firstFailed.Load() || ctx.Err() != nil {
+				return
+			}
+			end := start + size
+			if end > n {
+				end = n
+			}
+			fn := newTask(start, end)
+			for row := start; row < end; row++ {
+				if int64(row) > firstFailed.Load() {
+					// The ranges handed out later only hold rows after the failed one.
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				if err := fn(row); err != nil {
+					mu.Lock()
+					if rowErr == nil || row < rowErr.Row {
+						rowErr = &mangoerr.RowError{Row: row, Err: err}
+						firstFailed.Store(int64(row))
+					}
+					mu.Unlock()
+					return
+				}
+			}
+			done.Add(int64(end - start))
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run()
+		}()
+	}
+	wg.Wait()
+	if rowErr != nil {
+		return rowErr
+	}
+	if done.Load() == int64(n) {
+		return nil
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,64 @@
+package parallel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kstremick/mango/core/internal/parallel"
+	"github.com/kstremick/mango/core/mangoerr"
+
+	"github.com/zeebo/assert"
+)
+
+func TestForEach(t *testing.T) {
+	type testCase struct {
+		name string
+		n    int
+		// cancelAt cancels the context while processing the given row, or before starting if negative.
+		cancelAt int
+		failAt   int
+		err      error
+	}
+	failed := errors.New("failed")
+	testCases := []testCase{
+		{name: "all rows", n: 100, cancelAt: 1000, failAt: 1000},
+		{name: "cancelled after the last row", n: 100, cancelAt: 99, failAt: 1000},
+		{name: "cancelled before", n: 100, cancelAt: -1, failAt: 1000, err: context.Canceled},
+		{name: "failed row", n: 100, cancelAt: 1000, failAt: 42, err: failed},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelAt < 0 {
+				cancel()
+			}
+			processed := make([]bool, tc.n)
+			err := parallel.ForEach(ctx, tc.n, 1, func(start, end int) func(int) error {
+				return func(row int) error {
+					processed[row] = true
+					if row == tc.cancelAt {
+						cancel()
+					}
+					if row == tc.failAt {
+						return failed
+					}
+					return nil
+				}
+			})
+			if tc.err == nil {
+				assert.NoError(t, err)
+				for _, p := range processed {
+					assert.True(t, p)
+				}
+				return
+			}
+			assert.True(t, errors.Is(err, tc.err))
+			var rowErr *mangoerr.RowError
+			if errors.As(err, &rowErr) {
+				assert.Equal(t, tc.failAt, rowErr.Row)
+			}
+		})
+	}
+}
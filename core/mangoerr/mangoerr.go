@@ -0,0 +1,95 @@
+// Package mangoerr defines the errors returned by mango.
+// Errors wrap one of the sentinel errors below, so they can be matched with errors.Is,
+// and the typed errors carry the details of the failure for errors.As.
+// Errors of user-defined functions are wrapped in a RowError.
+package mangoerr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+var (
+	// ErrTypeMismatch is returned when a value or a Series does not have the expected type.
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrOutOfBounds is returned when an index, offset or length does not fit in a Series or DataFrame.
+	ErrOutOfBounds = errors.New("out of bounds")
+	// ErrColumnNotFound is returned when a DataFrame has no column of the given name.
+	ErrColumnNotFound = errors.New("column not found")
+	// ErrShapeMismatch is returned when the lengths of Series, or the shapes of DataFrames, do not match.
+	ErrShapeMismatch = errors.New("shape mismatch")
+)
+
+// TypeMismatchError reports a Series or a value whose type is not the expected one.
+type TypeMismatchError struct {
+	// Expected describes the expected type, such as "int64" or "numeric".
+	Expected string
+	Actual   arrow.DataType
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("expected %s, got %s", e.Expected, e.Actual)
+}
+
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// OutOfBoundsError reports an index outside of a Series or DataFrame of the given length.
+type OutOfBoundsError struct {
+	Index  int
+	Length int
+}
+
+func (e *OutOfBoundsError) Error() string {
+	return fmt.Sprintf("index %d out of bounds for length %d", e.Index, e.Length)
+}
+
+func (e *OutOfBoundsError) Is(target error) bool {
+	return target == ErrOutOfBounds
+}
+
+// ColumnNotFoundError reports a column missing from a DataFrame.
+type ColumnNotFoundError struct {
+	Name string
+}
+
+func (e *ColumnNotFoundError) Error() string {
+	return "column not found: " + e.Name
+}
+
+func (e *ColumnNotFoundError) Is(target error) bool {
+	return target == ErrColumnNotFound
+}
+
+// ShapeMismatchError reports a length, height or width that does not match the expected one.
+type ShapeMismatchError struct {
+	// What describes the mismatched dimension, such as "length of mask".
+	What     string
+	Expected int
+	Actual   int
+}
+
+func (e *ShapeMismatchError) Error() string {
+	return fmt.Sprintf("%s is %d, expected %d", e.What, e.Actual, e.Expected)
+}
+
+func (e *ShapeMismatchError) Is(target error) bool {
+	return target == ErrShapeMismatch
+}
+
+// RowError reports the row at which a user-defined function failed.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,61 @@
+package primitive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Compare returns -1, 0 or 1 if a is less than, equal to or greater than b.
+// Nulls are greater than every valid value, so they sort last.
+// Values of different types are compared by their string representation.
+func Compare(a, b Optional[interface{}]) int {
+	switch {
+	case !a.Valid && !b.Valid:
+		return 0
+	case !a.Valid:
+		return 1
+	case !b.Valid:
+		return -1
+	}
+	switch x := a.Value.(type) {
+	case int64:
+		if y, ok := b.Value.(int64); ok {
+			return compareOrdered(x, y)
+		}
+	case float64:
+		if y, ok := b.Value.(float64); ok {
+			return compareOrdered(x, y)
+		}
+	case string:
+		if y, ok := b.Value.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case bool:
+		if y, ok := b.Value.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case y:
+				return -1
+			default:
+				return 1
+			}
+		}
+	case time.Time:
+		if y, ok := b.Value.(time.Time); ok {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a.Value), fmt.Sprint(b.Value))
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
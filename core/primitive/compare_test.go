@@ -0,0 +1,29 @@
+package primitive_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/primitive"
+)
+
+func TestCompare(t *testing.T) {
+	type testData struct {
+		a, b     primitive.Optional[interface{}]
+		expected int
+	}
+	tests := []testData{
+		{a: primitive.Some[interface{}](int64(1)), b: primitive.Some[interface{}](int64(2)), expected: -1},
+		{a: primitive.Some[interface{}](2.5), b: primitive.Some[interface{}](1.5), expected: 1},
+		{a: primitive.Some[interface{}]("a"), b: primitive.Some[interface{}]("a"), expected: 0},
+		{a: primitive.Some[interface{}](false), b: primitive.Some[interface{}](true), expected: -1},
+		{a: primitive.None[interface{}](), b: primitive.Some[interface{}](int64(1)), expected: 1},
+		{a: primitive.Some[interface{}](int64(1)), b: primitive.None[interface{}](), expected: -1},
+		{a: primitive.None[interface{}](), b: primitive.None[interface{}](), expected: 0},
+	}
+
+	for _, test := range tests {
+		if cmp := primitive.Compare(test.a, test.b); cmp != test.expected {
+			t.Errorf("expected %d, got %d for %v and %v", test.expected, cmp, test.a, test.b)
+		}
+	}
+}
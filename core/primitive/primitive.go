@@ -3,6 +3,7 @@ package primitive
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -152,8 +153,20 @@ func getNil[T any]() T {
 
 func ToArrowDatatypeT[T Primitive]() arrow.DataType {
 	// We know T is a Primitive so it has a valid arrow DataType
-	ret, _ := ToArrowDatatype(getNil[T]())
-	return ret
+	if ret, err := ToArrowDatatype(getNil[T]()); err == nil {
+		return ret
+	}
+	// Named types like `type ID int64` are mapped from their underlying type.
+	switch reflect.TypeOf(getNil[T]()).Kind() {
+	case reflect.String:
+		return arrow.BinaryTypes.String
+	case reflect.Float64:
+		return arrow.PrimitiveTypes.Float64
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean
+	default:
+		return arrow.PrimitiveTypes.Int64
+	}
 }
 
 func AttemptConversionT[T Primitive](val interface{}) (T, bool) {
@@ -171,16 +184,16 @@ func AttemptConversionT[T Primitive](val interface{}) (T, bool) {
 	case bool:
 		if _, isInt := any(nilT).(int64); isInt {
 			if val {
-				converted = 1
+				converted = int64(1)
 			} else {
-				converted = 0
+				converted = int64(0)
 			}
 			ok = true
 		} else if _, isFloat := any(nilT).(float64); isFloat {
 			if val {
-				converted = 1.0
+				converted = float64(1)
 			} else {
-				converted = 0.0
+				converted = float64(0)
 			}
 			ok = true
 		} else if _, isString := any(nilT).(string); isString {
@@ -58,3 +58,14 @@ func TestToArrowDatatypeT(t *testing.T) {
 		}
 	}
 }
+
+func TestAttemptConversionFromBool(t *testing.T) {
+	i, ok := primitive.AttemptConversionT[int64](true)
+	if !ok || i != 1 {
+		t.Errorf("expected 1, got %v", i)
+	}
+	f, ok := primitive.AttemptConversionT[float64](false)
+	if !ok || f != 0 {
+		t.Errorf("expected 0, got %v", f)
+	}
+}
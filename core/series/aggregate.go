@@ -0,0 +1,126 @@
+package series
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kstremick/mango/core/primitive"
+)
+
+// Sum returns the sum of the non-null values of a numeric Series.
+func (s *Series) Sum() (float64, error) {
+	vals, err := s.nonNullFloat64Values()
+	if err != nil {
+		return 0, err
+	}
+	ret := 0.0
+	for _, v := range vals {
+		ret += v
+	}
+	return ret, nil
+}
+
+// Mean returns the mean of the non-null values of a numeric Series.
+// The result is null if there are no such values.
+func (s *Series) Mean() (primitive.Optional[float64], error) {
+	vals, err := s.nonNullFloat64Values()
+	if err != nil || len(vals) == 0 {
+		return primitive.None[float64](), err
+	}
+	ret := 0.0
+	for _, v := range vals {
+		ret += v
+	}
+	return primitive.Some(ret / float64(len(vals))), nil
+}
+
+// Min returns the minimum of the non-null values of a numeric Series.
+// The result is null if there are no such values.
+func (s *Series) Min() (primitive.Optional[float64], error) {
+	vals, err := s.nonNullFloat64Values()
+	if err != nil || len(vals) == 0 {
+		return primitive.None[float64](), err
+	}
+	ret := vals[0]
+	for _, v := range vals[1:] {
+		ret = math.Min(ret, v)
+	}
+	return primitive.Some(ret), nil
+}
+
+// Max returns the maximum of the non-null values of a numeric Series.
+// The result is null if there are no such values.
+func (s *Series) Max() (primitive.Optional[float64], error) {
+	vals, err := s.nonNullFloat64Values()
+	if err != nil || len(vals) == 0 {
+		return primitive.None[float64](), err
+	}
+	ret := vals[0]
+	for _, v := range vals[1:] {
+		ret = math.Max(ret, v)
+	}
+	return primitive.Some(ret), nil
+}
+
+// Var returns the sample variance of the non-null values of a numeric Series.
+// The result is null if there are fewer than two such values.
+func (s *Series) Var() (primitive.Optional[float64], error) {
+	vals, err := s.nonNullFloat64Values()
+	if err != nil {
+		return primitive.None[float64](), err
+	}
+	weights := make([]float64, len(vals))
+	for i := range weights {
+		weights[i] = 1
+	}
+	v, ok := variance(vals, weights)
+	if !ok {
+		return primitive.None[float64](), nil
+	}
+	return primitive.Some(v), nil
+}
+
+// Std returns the sample standard deviation of the non-null values of a numeric Series.
+// The result is null if there are fewer than two such values.
+func (s *Series) Std() (primitive.Optional[float64], error) {
+	v, err := s.Var()
+	if err != nil || !v.Valid {
+		return v, err
+	}
+	return primitive.Some(math.Sqrt(v.Value)), nil
+}
+
+// Median returns the median of the non-null values of a numeric Series.
+// The result is null if there are no such values.
+func (s *Series) Median() (primitive.Optional[float64], error) {
+	return s.Quantile(0.5)
+}
+
+// Quantile returns the quantile q of the non-null values of a numeric Series.
+// Values between two data points are linearly interpolated.
+// The result is null if there are no such values.
+func (s *Series) Quantile(q float64) (primitive.Optional[float64], error) {
+	if q < 0 || q > 1 {
+		return primitive.None[float64](), fmt.Errorf("quantile must be between 0 and 1, got %v", q)
+	}
+	vals, err := s.nonNullFloat64Values()
+	if err != nil || len(vals) == 0 {
+		return primitive.None[float64](), err
+	}
+	return primitive.Some(quantile(vals, q)), nil
+}
+
+// nonNullFloat64Values returns the non-null values of a numeric Series as float64.
+func (s *Series) nonNullFloat64Values() ([]float64, error) {
+	vals, valids, err := s.float64Values()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]float64, 0, len(vals))
+	for i, v := range vals {
+		if valids[i] {
+			ret = append(ret, v)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,60 @@
+package series_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	ser := series.NewSeries("test", []interface{}{int64(1), primitive.Null{}, int64(3), int64(5)})
+
+	sum, err := ser.Sum()
+	assert.NoError(t, err)
+	assert.Equal(t, 9.0, sum)
+
+	type testCase struct {
+		name     string
+		fn       func() (primitive.Optional[float64], error)
+		expected primitive.Optional[float64]
+	}
+	testCases := []testCase{
+		{name: "mean", fn: ser.Mean, expected: primitive.Some(3.0)},
+		{name: "min", fn: ser.Min, expected: primitive.Some(1.0)},
+		{name: "max", fn: ser.Max, expected: primitive.Some(5.0)},
+		{name: "var", fn: ser.Var, expected: primitive.Some(4.0)},
+		{name: "median", fn: ser.Median, expected: primitive.Some(3.0)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.fn()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+
+	single := series.NewSeries("test", []float64{1})
+	std, err := single.Std()
+	assert.NoError(t, err)
+	assert.False(t, std.Valid)
+
+	strs := series.NewSeries("test", []string{"a"})
+	_, err = strs.Mean()
+	assert.Error(t, err)
+}
+
+func TestTake(t *testing.T) {
+	ser := chunkedInt64Series("test", []int64{1, 0}, []int64{3, 4})
+	indices := series.NewSeriesTFromTSlice("", []int64{3, 1, 0, 0, 2}, nil)
+	res, err := ser.Take(&indices)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", res.Name)
+	assert.DeepEqual(t, []interface{}{int64(4), nil, int64(1), int64(1), int64(3)}, values(res))
+
+	indices = series.NewSeriesTFromTSlice("", []int64{5}, nil)
+	_, err = ser.Take(&indices)
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package series
 import (
 	"fmt"
 
+	"github.com/kstremick/mango/core/mangoerr"
+
 	"github.com/apache/arrow/go/v12/arrow"
 )
 
@@ -10,11 +12,11 @@ import (
 // Returns an error for non-bool series
 func (s *Series) Any() (bool, error) {
 	if s.Type() != arrow.BOOL {
-		return false, fmt.Errorf("Any() expected boolean, got %s", s.Type())
+		return false, fmt.Errorf("Any(): %w", &mangoerr.TypeMismatchError{Expected: "bool", Actual: s.DataType()})
 	}
 
 	for i := 0; i < s.Len(); i++ {
-		if s.ValueExn(i).Value.(bool) {
+		if v := s.ValueExn(i); v.Valid && v.Value.(bool) {
 			return true, nil
 		}
 	}
@@ -25,11 +27,11 @@ func (s *Series) Any() (bool, error) {
 // Returns an error for non-bool series
 func (s *Series) All() (bool, error) {
 	if s.Type() != arrow.BOOL {
-		return false, fmt.Errorf("All() expected boolean, got %s", s.Type())
+		return false, fmt.Errorf("All(): %w", &mangoerr.TypeMismatchError{Expected: "bool", Actual: s.DataType()})
 	}
 
 	for i := 0; i < s.Len(); i++ {
-		if !s.ValueExn(i).Value.(bool) {
+		if v := s.ValueExn(i); v.Valid && !v.Value.(bool) {
 			return false, nil
 		}
 	}
@@ -0,0 +1,117 @@
+package series
+
+import (
+	"reflect"
+
+	"github.com/kstremick/mango/core/chunked"
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// newArrayT builds an arrow array of type T from vals.
+// The valid slice must either be nil or be equal in length to vals.
+func newArrayT[T primitive.Primitive](vals []T, valid []bool) arrow.Array {
+	mem := DefaultAllocator()
+	switch vals := any(vals).(type) {
+	case []string:
+		b := array.NewStringBuilder(mem)
+		defer b.Release()
+		b.AppendValues(vals, valid)
+		return b.NewArray()
+	case []float64:
+		b := array.NewFloat64Builder(mem)
+		defer b.Release()
+		b.AppendValues(vals, valid)
+		return b.NewArray()
+	case []bool:
+		b := array.NewBooleanBuilder(mem)
+		defer b.Release()
+		b.AppendValues(vals, valid)
+		return b.NewArray()
+	case []int64:
+		b := array.NewInt64Builder(mem)
+		defer b.Release()
+		b.AppendValues(vals, valid)
+		return b.NewArray()
+	}
+	// T is a Primitive, so this is only reachable for named types like `type ID int64`.
+	return newNamedArrayT(vals, valid)
+}
+
+// newNamedArrayT builds an arrow array from vals of a named type like `type ID int64`,
+// converting them to their underlying type.
+func newNamedArrayT[T primitive.Primitive](vals []T, valid []bool) arrow.Array {
+	v := reflect.ValueOf(vals)
+	switch v.Type().Elem().Kind() {
+	case reflect.String:
+		conv := make([]string, len(vals))
+		for i := range conv {
+			conv[i] = v.Index(i).String()
+		}
+		return newArrayT(conv, valid)
+	case reflect.Float64:
+		conv := make([]float64, len(vals))
+		for i := range conv {
+			conv[i] = v.Index(i).Float()
+		}
+		return newArrayT(conv, valid)
+	case reflect.Bool:
+		conv := make([]bool, len(vals))
+		for i := range conv {
+			conv[i] = v.Index(i).Bool()
+		}
+		return newArrayT(conv, valid)
+	default:
+		conv := make([]int64, len(vals))
+		for i := range conv {
+			conv[i] = v.Index(i).Int()
+		}
+		return newArrayT(conv, valid)
+	}
+}
+
+// newSeriesT creates a new Series of type T from vals and their validity.
+func newSeriesT[T primitive.Primitive](name string, vals []T, valid []bool) Series {
+	arr := newArrayT(vals, valid)
+	defer arr.Release()
+	return NewSeriesFromArray(name, arr)
+}
+
+// valuesT flattens the chunks of the Series into a single slice of type T.
+// It also returns the validity of each value.
+func valuesT[T primitive.Primitive](s *Series) ([]T, []bool, error) {
+	vals := make([]T, 0, s.Len())
+	valids := make([]bool, 0, s.Len())
+	for _, chunk := range s.Chunks() {
+		chunkVals, chunkValids, err := chunked.ExtractChunk[T](chunk)
+		if err != nil {
+			return nil, nil, err
+		}
+		vals = append(vals, chunkVals...)
+		valids = append(valids, chunkValids...)
+	}
+	return vals, valids, nil
+}
+
+// float64Values flattens a numeric Series into a slice of float64.
+// It also returns the validity of each value.
+func (s *Series) float64Values() ([]float64, []bool, error) {
+	switch s.Type() {
+	case arrow.FLOAT64:
+		return valuesT[float64](s)
+	case arrow.INT64:
+		ints, valids, err := valuesT[int64](s)
+		if err != nil {
+			return nil, nil, err
+		}
+		vals := make([]float64, len(ints))
+		for i, v := range ints {
+			vals[i] = float64(v)
+		}
+		return vals, valids, nil
+	}
+	return nil, nil, &mangoerr.TypeMismatchError{Expected: "numeric", Actual: s.DataType()}
+}
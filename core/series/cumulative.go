@@ -0,0 +1,239 @@
+package series
+
+import (
+	"fmt"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/utils"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// CumSum returns the cumulative sum of the Series.
+// Nulls are skipped, and stay null in the result.
+// If reverse is true, the sum is accumulated from the end of the Series.
+func (s *Series) CumSum(reverse bool) (Series, error) {
+	return s.cumulate(reverse, sum[int64], sum[float64])
+}
+
+// CumProd returns the cumulative product of the Series.
+// Nulls are skipped, and stay null in the result.
+// If reverse is true, the product is accumulated from the end of the Series.
+func (s *Series) CumProd(reverse bool) (Series, error) {
+	return s.cumulate(reverse, prod[int64], prod[float64])
+}
+
+// CumMin returns the cumulative minimum of the Series.
+// Nulls are skipped, and stay null in the result.
+// If reverse is true, the minimum is accumulated from the end of the Series.
+func (s *Series) CumMin(reverse bool) (Series, error) {
+	return s.cumulate(reverse, minOf[int64], minOf[float64])
+}
+
+// CumMax returns the cumulative maximum of the Series.
+// Nulls are skipped, and stay null in the result.
+// If reverse is true, the maximum is accumulated from the end of the Series.
+func (s *Series) CumMax(reverse bool) (Series, error) {
+	return s.cumulate(reverse, maxOf[int64], maxOf[float64])
+}
+
+// CumCount returns the cumulative count of non-null values as an int64 Series.
+// If reverse is true, the count is accumulated from the end of the Series.
+func (s *Series) CumCount(reverse bool) Series {
+	n := s.Len()
+	rets := make([]int64, n)
+	var count int64
+	for k := 0; k < n; k++ {
+		i := k
+		if reverse {
+			i = n - 1 - k
+		}
+		if s.IsValidExn(i) {
+			count++
+		}
+		rets[i] = count
+	}
+	return newSeriesT(s.Name, rets, nil)
+}
+
+// Shift shifts the values of the Series by n positions.
+// A positive n shifts values towards the end, a negative n towards the start.
+// The positions left empty are filled with fill, which is cast to the type of the Series.
+// Pass primitive.None to fill with nulls. This operation does not copy the data.
+func (s *Series) Shift(n int, fill primitive.Optional[interface{}]) (Series, error) {
+	length := s.Len()
+	if n == 0 {
+		s.Retain()
+		return s.Copy(), nil
+	}
+	shift := n
+	if shift < 0 {
+		shift = -shift
+	}
+	if shift > length {
+		shift = length
+	}
+	fillArr, err := newConstantArray(s.DataType(), fill, shift)
+	if err != nil {
+		return Series{}, err
+	}
+	defer fillArr.Release()
+
+	var chunks []arrow.Array
+	if n > 0 {
+		body := array.NewChunkedSlice(s.ca, 0, int64(length-shift))
+		defer body.Release()
+		chunks = append([]arrow.Array{fillArr}, body.Chunks()...)
+	} else {
+		body := array.NewChunkedSlice(s.ca, int64(shift), int64(length))
+		defer body.Release()
+		chunks = append(body.Chunks(), fillArr)
+	}
+	return NewSeriesFromChunked(s.Name, arrow.NewChunked(s.DataType(), chunks)), nil
+}
+
+// Diff returns the difference between each value and the value n positions before it.
+// A negative n compares with the value n positions after it.
+// The result is null where either value is null or out of range.
+func (s *Series) Diff(n int) (Series, error) {
+	switch s.Type() {
+	case arrow.INT64:
+		return diffT[int64](s, n)
+	case arrow.FLOAT64:
+		return diffT[float64](s, n)
+	}
+	return Series{}, fmt.Errorf("Diff(): %w", &mangoerr.TypeMismatchError{Expected: "numeric", Actual: s.DataType()})
+}
+
+// PctChange returns the fractional change between each value and the value n positions before it,
+// as a float64 Series.
+// A negative n compares with the value n positions after it.
+// The result is null where either value is null or out of range.
+func (s *Series) PctChange(n int) (Series, error) {
+	vals, valids, err := s.float64Values()
+	if err != nil {
+		return Series{}, err
+	}
+	rets := make([]float64, len(vals))
+	retValids := make([]bool, len(vals))
+	for i := range vals {
+		j := i - n
+		if j < 0 || j >= len(vals) || !valids[i] || !valids[j] {
+			continue
+		}
+		rets[i] = (vals[i] - vals[j]) / vals[j]
+		retValids[i] = true
+	}
+	return newSeriesT(s.Name, rets, retValids), nil
+}
+
+// cumulate accumulates the values of a numeric Series with the function matching its type.
+func (s *Series) cumulate(reverse bool, intFn func(int64, int64) int64, floatFn func(float64, float64) float64) (Series, error) {
+	switch s.Type() {
+	case arrow.INT64:
+		return cumulateT(s, reverse, intFn)
+	case arrow.FLOAT64:
+		return cumulateT(s, reverse, floatFn)
+	}
+	return Series{}, &mangoerr.TypeMismatchError{Expected: "numeric", Actual: s.DataType()}
+}
+
+func cumulateT[T int64 | float64](s *Series, reverse bool, fn func(T, T) T) (Series, error) {
+	vals, valids, err := valuesT[T](s)
+	if err != nil {
+		return Series{}, err
+	}
+	n := len(vals)
+	rets := make([]T, n)
+	var acc T
+	started := false
+	for k := 0; k < n; k++ {
+		i := k
+		if reverse {
+			i = n - 1 - k
+		}
+		if !valids[i] {
+			continue
+		}
+		if started {
+			acc = fn(acc, vals[i])
+		} else {
+			acc = vals[i]
+			started = true
+		}
+		rets[i] = acc
+	}
+	return newSeriesT(s.Name, rets, valids), nil
+}
+
+func diffT[T int64 | float64](s *Series, n int) (Series, error) {
+	vals, valids, err := valuesT[T](s)
+	if err != nil {
+		return Series{}, err
+	}
+	rets := make([]T, len(vals))
+	retValids := make([]bool, len(vals))
+	for i := range vals {
+		j := i - n
+		if j < 0 || j >= len(vals) || !valids[i] || !valids[j] {
+			continue
+		}
+		rets[i] = vals[i] - vals[j]
+		retValids[i] = true
+	}
+	return newSeriesT(s.Name, rets, retValids), nil
+}
+
+// newConstantArray builds an array of length n where every value is val, cast to dtype.
+func newConstantArray(dtype arrow.DataType, val primitive.Optional[interface{}], n int) (arrow.Array, error) {
+	if !val.Valid {
+		return array.MakeArrayOfNull(DefaultAllocator(), dtype, n), nil
+	}
+	switch dtype.ID() {
+	case arrow.STRING:
+		return repeatT[string](val.Value, n)
+	case arrow.FLOAT64:
+		return repeatT[float64](val.Value, n)
+	case arrow.BOOL:
+		return repeatT[bool](val.Value, n)
+	case arrow.INT64:
+		return repeatT[int64](val.Value, n)
+	}
+	return nil, &mangoerr.TypeMismatchError{Expected: "string, float64, bool or int64", Actual: dtype}
+}
+
+func repeatT[T primitive.Primitive](val interface{}, n int) (arrow.Array, error) {
+	v, ok := primitive.AttemptConversionT[T](val)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot convert %v to %s", mangoerr.ErrTypeMismatch, val, primitive.ToArrowDatatypeT[T]())
+	}
+	vals := make([]T, n)
+	for i := range vals {
+		vals[i] = v
+	}
+	return newArrayT(vals, nil), nil
+}
+
+func sum[T utils.Number](a, b T) T {
+	return a + b
+}
+
+func prod[T utils.Number](a, b T) T {
+	return a * b
+}
+
+func minOf[T utils.Number](a, b T) T {
+	if b < a {
+		return b
+	}
+	return a
+}
+
+func maxOf[T utils.Number](a, b T) T {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,126 @@
+package series_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/zeebo/assert"
+)
+
+// chunkedInt64Series creates an int64 Series with one chunk per slice of values.
+// Zeros are treated as nulls.
+func chunkedInt64Series(name string, chunks ...[]int64) series.Series {
+	arrs := make([]arrow.Array, len(chunks))
+	for i, chunk := range chunks {
+		b := array.NewInt64Builder(memory.NewGoAllocator())
+		for _, v := range chunk {
+			if v == 0 {
+				b.AppendNull()
+			} else {
+				b.Append(v)
+			}
+		}
+		arrs[i] = b.NewArray()
+		b.Release()
+	}
+	return series.NewSeriesFromChunked(name, arrow.NewChunked(arrow.PrimitiveTypes.Int64, arrs))
+}
+
+func TestCumulative(t *testing.T) {
+	ser := chunkedInt64Series("test", []int64{1, 2}, []int64{0, 3, 4})
+	assert.Equal(t, 2, ser.NumChunks())
+
+	type testCase struct {
+		name     string
+		fn       func(bool) (series.Series, error)
+		reverse  bool
+		expected []interface{}
+	}
+	testCases := []testCase{
+		{name: "sum", fn: ser.CumSum, expected: []interface{}{int64(1), int64(3), nil, int64(6), int64(10)}},
+		{name: "sum reverse", fn: ser.CumSum, reverse: true, expected: []interface{}{int64(10), int64(9), nil, int64(7), int64(4)}},
+		{name: "prod", fn: ser.CumProd, expected: []interface{}{int64(1), int64(2), nil, int64(6), int64(24)}},
+		{name: "min reverse", fn: ser.CumMin, reverse: true, expected: []interface{}{int64(1), int64(2), nil, int64(3), int64(4)}},
+		{name: "max", fn: ser.CumMax, expected: []interface{}{int64(1), int64(2), nil, int64(3), int64(4)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.fn(tc.reverse)
+			assert.NoError(t, err)
+			assert.Equal(t, arrow.INT64, res.Type())
+			assert.DeepEqual(t, tc.expected, values(res))
+		})
+	}
+
+	count := ser.CumCount(false)
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(2), int64(3), int64(4)}, values(count))
+	count = ser.CumCount(true)
+	assert.DeepEqual(t, []interface{}{int64(4), int64(3), int64(2), int64(2), int64(1)}, values(count))
+
+	floats := series.NewSeries("test", []float64{1.5, 2.5})
+	res, err := floats.CumSum(false)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{1.5, 4.0}, values(res))
+
+	strs := series.NewSeries("test", []string{"a"})
+	_, err = strs.CumSum(false)
+	assert.Error(t, err)
+}
+
+func TestShift(t *testing.T) {
+	ser := chunkedInt64Series("test", []int64{1, 2}, []int64{3, 4, 5})
+
+	shifted, err := ser.Shift(2, primitive.None[interface{}]())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, shifted.Len())
+	assert.DeepEqual(t, []interface{}{nil, nil, int64(1), int64(2), int64(3)}, values(shifted))
+
+	shifted, err = ser.Shift(-3, primitive.Some[interface{}](int64(0)))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(4), int64(5), int64(0), int64(0), int64(0)}, values(shifted))
+
+	shifted, err = ser.Shift(10, primitive.None[interface{}]())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, shifted.NullN())
+
+	shifted, err = ser.Shift(0, primitive.None[interface{}]())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, values(ser), values(shifted))
+
+	_, err = ser.Shift(1, primitive.Some[interface{}]("a"))
+	assert.Error(t, err)
+
+	strs := series.NewSeries("test", []string{"a", "b"})
+	shifted, err = strs.Shift(1, primitive.Some[interface{}]("z"))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{"z", "a"}, values(shifted))
+}
+
+func TestDiffAndPctChange(t *testing.T) {
+	ser := chunkedInt64Series("test", []int64{1, 2}, []int64{4, 0, 16})
+
+	diff, err := ser.Diff(1)
+	assert.NoError(t, err)
+	assert.Equal(t, arrow.INT64, diff.Type())
+	assert.DeepEqual(t, []interface{}{nil, int64(1), int64(2), nil, nil}, values(diff))
+
+	diff, err = ser.Diff(-2)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(-3), nil, int64(-12), nil, nil}, values(diff))
+
+	pct, err := ser.PctChange(1)
+	assert.NoError(t, err)
+	assert.Equal(t, arrow.FLOAT64, pct.Type())
+	assert.DeepEqual(t, []interface{}{nil, 1.0, 1.0, nil, nil}, values(pct))
+
+	strs := series.NewSeries("test", []string{"a"})
+	_, err = strs.Diff(1)
+	assert.Error(t, err)
+	_, err = strs.PctChange(1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package series_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+type seriesID int64
+
+func TestSeriesErrors(t *testing.T) {
+	ints := series.NewSeries("a", []int64{1, 2, 3})
+	strs := series.NewSeries("b", []string{"x", "y", "z"})
+	mask := series.NewSeriesTFromTSlice("m", []bool{true, false}, nil)
+	indices := series.NewSeriesTFromTSlice("i", []int64{0, 5}, nil)
+
+	type testCase struct {
+		name   string
+		fn     func() error
+		target error
+	}
+	testCases := []testCase{
+		{"empty interface slice", func() error {
+			_, err := series.NewSeriesChecked("a", []interface{}{})
+			return err
+		}, mangoerr.ErrTypeMismatch},
+		{"unsupported value", func() error {
+			_, err := series.NewSeriesChecked("a", struct{}{})
+			return err
+		}, mangoerr.ErrTypeMismatch},
+		{"valid length", func() error {
+			_, err := series.NewSeriesFromSliceChecked("a", []int64{1, 2}, []bool{true}, false)
+			return err
+		}, mangoerr.ErrShapeMismatch},
+		{"typed valid length", func() error {
+			_, err := series.NewSeriesTFromTSliceChecked("a", []int64{1, 2}, []bool{true})
+			return err
+		}, mangoerr.ErrShapeMismatch},
+		{"value", func() error { _, err := ints.Value(3); return err }, mangoerr.ErrOutOfBounds},
+		{"negative value", func() error { _, err := ints.Value(-1); return err }, mangoerr.ErrOutOfBounds},
+		{"is valid", func() error { _, err := ints.IsValid(3); return err }, mangoerr.ErrOutOfBounds},
+		{"slice", func() error { _, err := ints.Slice(2, 2); return err }, mangoerr.ErrOutOfBounds},
+		{"head", func() error { _, err := ints.Head(4); return err }, mangoerr.ErrOutOfBounds},
+		{"take", func() error { _, err := ints.Take(&indices); return err }, mangoerr.ErrOutOfBounds},
+		{"take every", func() error { _, err := ints.TakeEvery(0); return err }, mangoerr.ErrOutOfBounds},
+		{"filter", func() error { _, err := ints.Filter(&mask); return err }, mangoerr.ErrShapeMismatch},
+		{"append", func() error { _, err := ints.Append(strs); return err }, mangoerr.ErrTypeMismatch},
+		{"cast", func() error { _, err := strs.Cast(arrow.PrimitiveTypes.Int64); return err }, mangoerr.ErrTypeMismatch},
+		{"any", func() error { _, err := ints.Any(); return err }, mangoerr.ErrTypeMismatch},
+		{"diff", func() error { _, err := strs.Diff(1); return err }, mangoerr.ErrTypeMismatch},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, tc.target))
+		})
+	}
+
+	_, err := ints.Value(5)
+	var oob *mangoerr.OutOfBoundsError
+	assert.True(t, errors.As(err, &oob))
+	assert.Equal(t, oob.Index, 5)
+	assert.Equal(t, oob.Length, 3)
+
+	_, err = ints.Append(strs)
+	var mismatch *mangoerr.TypeMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, mismatch.Actual, arrow.BinaryTypes.String)
+}
+
+func TestEmptySeries(t *testing.T) {
+	empty := series.NewEmptySeries("a", arrow.FixedWidthTypes.Timestamp_us)
+	assert.Equal(t, empty.Len(), 0)
+	assert.Equal(t, empty.DataType(), arrow.FixedWidthTypes.Timestamp_us)
+
+	floats := series.NewSeries("b", []float64{})
+	assert.Equal(t, floats.Len(), 0)
+	assert.Equal(t, floats.DataType(), arrow.PrimitiveTypes.Float64)
+
+	ids := series.NewSeriesTFromTSlice("c", []seriesID{1, 2}, nil)
+	assert.Equal(t, ids.DataType(), arrow.PrimitiveTypes.Int64)
+	assert.Equal(t, ids.ValueExn(1).Value, int64(2))
+}
+
+func TestAnyAndAllSkipNulls(t *testing.T) {
+	s := series.NewSeriesFromSlice("a", []bool{true, false}, []bool{true, false}, false)
+	anyTrue, err := s.Any()
+	assert.NoError(t, err)
+	assert.True(t, anyTrue)
+	allTrue, err := s.All()
+	assert.NoError(t, err)
+	assert.True(t, allTrue)
+}
@@ -0,0 +1,88 @@
+package series
+
+import (
+	"github.com/kstremick/mango/core/chunked"
+	"github.com/kstremick/mango/core/primitive"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+// Iterator walks the values of a Series in order, one chunk after the other.
+// Unlike calling Value for every index, it does not resolve the chunk of each value.
+//
+//	it := s.Iter()
+//	for it.Next() {
+//		v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	chunks  []arrow.Array
+	extract func(arrow.Array) (func(int) T, error)
+	// chunk is the index of the current chunk, and i the index of the current value within it.
+	chunk int
+	i     int
+	index int
+	fn    func(int) T
+	value primitive.Optional[T]
+	err   error
+}
+
+func newIterator[T any](s *Series, extract func(arrow.Array) (func(int) T, error)) *Iterator[T] {
+	return &Iterator[T]{chunks: s.Chunks(), extract: extract, i: -1, index: -1}
+}
+
+// Iter returns an iterator over the values of the Series.
+func (s *Series) Iter() *Iterator[interface{}] {
+	return newIterator(s, chunked.ExtractValueFn)
+}
+
+// Iter returns an iterator over the values of the Series, typed as T.
+func (s *SeriesT[T]) Iter() *Iterator[T] {
+	return newIterator(&s.Series, chunked.ExtractValueFnT[T])
+}
+
+// Next advances the iterator to the next value, returning false after the last value or on errors.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.i++
+	for it.chunk < len(it.chunks) && it.i >= it.chunks[it.chunk].Len() {
+		it.chunk++
+		it.i = 0
+		it.fn = nil
+	}
+	if it.chunk >= len(it.chunks) {
+		return false
+	}
+	chunk := it.chunks[it.chunk]
+	if it.fn == nil {
+		if it.fn, it.err = it.extract(chunk); it.err != nil {
+			return false
+		}
+	}
+	it.index++
+	if chunk.IsNull(it.i) {
+		it.value = primitive.None[T]()
+	} else {
+		it.value = primitive.Some(it.fn(it.i))
+	}
+	return true
+}
+
+// Value returns the current value.
+func (it *Iterator[T]) Value() primitive.Optional[T] {
+	return it.value
+}
+
+// Index returns the index of the current value in the Series.
+func (it *Iterator[T]) Index() int {
+	return it.index
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,62 @@
+package series_test
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+// chunkedInts returns an int64 Series with chunks [1, null], [], [3].
+func chunkedInts(t *testing.T) series.Series {
+	s := series.NewSeriesFromSlice("a", []int64{1, 0}, []bool{true, false}, false)
+	s, err := s.Append(series.NewSeries("a", []int64{}))
+	assert.NoError(t, err)
+	s, err = s.Append(series.NewSeries("a", []int64{3}))
+	assert.NoError(t, err)
+	return s
+}
+
+func TestResolveIndex(t *testing.T) {
+	s := chunkedInts(t)
+	type testCase struct {
+		index, chunk, offset int
+	}
+	testCases := []testCase{{0, 0, 0}, {1, 0, 1}, {2, 2, 0}}
+	for _, tc := range testCases {
+		chunk, offset := s.ResolveIndex(tc.index)
+		assert.Equal(t, tc.chunk, chunk)
+		assert.Equal(t, tc.offset, offset)
+	}
+	sliced, err := s.Slice(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), sliced.ValueExn(1).Value)
+}
+
+func TestIter(t *testing.T) {
+	s := chunkedInts(t)
+	want := []primitive.Optional[int64]{primitive.Some(int64(1)), primitive.None[int64](), primitive.Some(int64(3))}
+
+	typed := series.SeriesT[int64]{Series: s}
+	var got []primitive.Optional[int64]
+	it := typed.Iter()
+	for it.Next() {
+		assert.Equal(t, len(got), it.Index())
+		got = append(got, it.Value())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, want, got)
+
+	var untyped []interface{}
+	for it := s.Iter(); it.Next(); {
+		untyped = append(untyped, it.Value().Value)
+	}
+	assert.Equal(t, []interface{}{int64(1), nil, int64(3)}, untyped)
+
+	wrong := series.SeriesT[string]{Series: s}
+	wrongIt := wrong.Iter()
+	assert.False(t, wrongIt.Next())
+	assert.Error(t, wrongIt.Err())
+}
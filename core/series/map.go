@@ -0,0 +1,176 @@
+package series
+
+import (
+	"reflect"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// BuilderT builds a typed Series one value at a time,
+// appending the values to an arrow builder without converting them to interface{}.
+// Call Release once the builder is no longer needed.
+type BuilderT[T primitive.Primitive] struct {
+	b      array.Builder
+	append func(T)
+}
+
+// NewBuilderT creates a new BuilderT with room for capacity values.
+func NewBuilderT[T primitive.Primitive](capacity int) *BuilderT[T] {
+	b := array.NewBuilder(DefaultAllocator(), primitive.ToArrowDatatypeT[T]())
+	b.Reserve(capacity)
+	return &BuilderT[T]{b: b, append: appendFnT[T](b)}
+}
+
+// appendFnT returns a function appending values of type T to b, which is the builder of the datatype of T.
+// Values of named types like `type ID int64` are converted to their underlying type.
+func appendFnT[T primitive.Primitive](b array.Builder) func(T) {
+	_, named := primitive.ToArrowDatatype(*new(T))
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		if named != nil {
+			return func(v T) { b.Append(reflect.ValueOf(v).String()) }
+		}
+		return func(v T) { b.Append(any(v).(string)) }
+	case *array.Float64Builder:
+		if named != nil {
+			return func(v T) { b.Append(reflect.ValueOf(v).Float()) }
+		}
+		return func(v T) { b.Append(any(v).(float64)) }
+	case *array.BooleanBuilder:
+		if named != nil {
+			return func(v T) { b.Append(reflect.ValueOf(v).Bool()) }
+		}
+		return func(v T) { b.Append(any(v).(bool)) }
+	default:
+		ib := b.(*array.Int64Builder)
+		if named != nil {
+			return func(v T) { ib.Append(reflect.ValueOf(v).Int()) }
+		}
+		return func(v T) { ib.Append(any(v).(int64)) }
+	}
+}
+
+// Append appends a valid value.
+func (b *BuilderT[T]) Append(v T) {
+	b.append(v)
+}
+
+// AppendNull appends a null.
+func (b *BuilderT[T]) AppendNull() {
+	b.b.AppendNull()
+}
+
+// AppendOptional appends a value, or a null if it is not valid.
+func (b *BuilderT[T]) AppendOptional(v primitive.Optional[T]) {
+	if v.Valid {
+		b.append(v.Value)
+	} else {
+		b.b.AppendNull()
+	}
+}
+
+// Len returns the number of values appended since the last call to NewSeries.
+func (b *BuilderT[T]) Len() int {
+	return b.b.Len()
+}
+
+// NewSeries returns a new Series of the appended values, and resets the builder.
+func (b *BuilderT[T]) NewSeries(name string) SeriesT[T] {
+	arr := b.b.NewArray()
+	defer arr.Release()
+	return SeriesT[T]{Series: NewSeriesFromArray(name, arr)}
+}
+
+// Release frees the memory of the builder, including the values appended since the last call to NewSeries.
+func (b *BuilderT[T]) Release() {
+	b.b.Release()
+}
+
+// Map applies fn to every valid value of s, returning a Series of type U.
+// Nulls stay null, and fn is not called for them.
+// Returns an error if the values of s cannot be read as T.
+func Map[T, U primitive.Primitive](s SeriesT[T], fn func(T) U) (SeriesT[U], error) {
+	return MapOptional(s, func(v primitive.Optional[T]) primitive.Optional[U] {
+		if !v.Valid {
+			return primitive.None[U]()
+		}
+		return primitive.Some(fn(v.Value))
+	})
+}
+
+// MapOptional applies fn to every value of s, including nulls, returning a Series of type U.
+// Returns an error if the values of s cannot be read as T.
+func MapOptional[T, U primitive.Primitive](s SeriesT[T], fn func(primitive.Optional[T]) primitive.Optional[U]) (SeriesT[U], error) {
+	b := NewBuilderT[U](s.Len())
+	defer b.Release()
+	it := s.Iter()
+	for it.Next() {
+		b.AppendOptional(fn(it.Value()))
+	}
+	if err := it.Err(); err != nil {
+		return SeriesT[U]{}, err
+	}
+	return b.NewSeries(s.Name), nil
+}
+
+// Zip2 applies fn to the values at the same index of a and b, returning a Series of type U named after a.
+// The result is null where any value is null.
+// Returns an error if the Series have different lengths, or if their values cannot be read.
+func Zip2[A, B, U primitive.Primitive](a SeriesT[A], b SeriesT[B], fn func(A, B) U) (SeriesT[U], error) {
+	if a.Len() != b.Len() {
+		return SeriesT[U]{}, &mangoerr.ShapeMismatchError{What: "length of " + b.Name, Expected: a.Len(), Actual: b.Len()}
+	}
+	out := NewBuilderT[U](a.Len())
+	defer out.Release()
+	itA, itB := a.Iter(), b.Iter()
+	for itA.Next() && itB.Next() {
+		va, vb := itA.Value(), itB.Value()
+		if va.Valid && vb.Valid {
+			out.Append(fn(va.Value, vb.Value))
+		} else {
+			out.AppendNull()
+		}
+	}
+	if err := firstErr(itA.Err(), itB.Err()); err != nil {
+		return SeriesT[U]{}, err
+	}
+	return out.NewSeries(a.Name), nil
+}
+
+// Zip3 is like Zip2, for three Series.
+func Zip3[A, B, C, U primitive.Primitive](a SeriesT[A], b SeriesT[B], c SeriesT[C], fn func(A, B, C) U) (SeriesT[U], error) {
+	if a.Len() != b.Len() {
+		return SeriesT[U]{}, &mangoerr.ShapeMismatchError{What: "length of " + b.Name, Expected: a.Len(), Actual: b.Len()}
+	}
+	if a.Len() != c.Len() {
+		return SeriesT[U]{}, &mangoerr.ShapeMismatchError{What: "length of " + c.Name, Expected: a.Len(), Actual: c.Len()}
+	}
+	out := NewBuilderT[U](a.Len())
+	defer out.Release()
+	itA, itB, itC := a.Iter(), b.Iter(), c.Iter()
+	for itA.Next() && itB.Next() && itC.Next() {
+		va, vb, vc := itA.Value(), itB.Value(), itC.Value()
+		if va.Valid && vb.Valid && vc.Valid {
+			out.Append(fn(va.Value, vb.Value, vc.Value))
+		} else {
+			out.AppendNull()
+		}
+	}
+	if err := firstErr(itA.Err(), itB.Err(), itC.Err()); err != nil {
+		return SeriesT[U]{}, err
+	}
+	return out.NewSeries(a.Name), nil
+}
+
+// firstErr returns the first of errs that is not nil.
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package series_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestMap(t *testing.T) {
+	s := series.NewSeriesTFromTSlice("a", []int64{1, 0, 3}, []bool{true, false, true})
+
+	calls := 0
+	strs, err := series.Map(s, func(v int64) string {
+		calls++
+		return fmt.Sprint(v * 2)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "a", strs.Name)
+	vals, valids := strs.ToSliceExn()
+	assert.Equal(t, []string{"2", "", "6"}, vals)
+	assert.Equal(t, []bool{true, false, true}, valids)
+
+	filled, err := series.MapOptional(s, func(v primitive.Optional[int64]) primitive.Optional[float64] {
+		if !v.Valid {
+			return primitive.Some(-1.0)
+		}
+		if v.Value == 3 {
+			return primitive.None[float64]()
+		}
+		return primitive.Some(float64(v.Value) / 2)
+	})
+	assert.NoError(t, err)
+	floats, valids := filled.ToSliceExn()
+	assert.Equal(t, []float64{0.5, -1, 0}, floats)
+	assert.Equal(t, []bool{true, true, false}, valids)
+
+	ids := series.NewSeriesTFromTSlice("id", []seriesID{4, 5}, nil)
+	doubled, err := series.Map(ids, func(v seriesID) seriesID { return v * 2 })
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.Some(seriesID(10)), doubled.ValueExn(1))
+
+	// The values of a Series of the wrong type cannot be read.
+	words := series.NewSeries("s", []string{"x"})
+	_, err = series.Map(series.NewSeriesTFromArray[int64]("s", words.Chunks()[0]), func(v int64) int64 { return v })
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+}
+
+func TestZip(t *testing.T) {
+	a := series.NewSeriesTFromTSlice("a", []int64{1, 2, 3}, nil)
+	b := series.NewSeriesTFromTSlice("b", []float64{0.5, 0, 1.5}, []bool{true, false, true})
+	c := series.NewSeriesTFromTSlice("c", []string{"x", "y", "z"}, nil)
+
+	sums, err := series.Zip2(a, b, func(x int64, y float64) float64 { return float64(x) + y })
+	assert.NoError(t, err)
+	vals, valids := sums.ToSliceExn()
+	assert.Equal(t, []float64{1.5, 0, 4.5}, vals)
+	assert.Equal(t, []bool{true, false, true}, valids)
+
+	labels, err := series.Zip3(a, b, c, func(x int64, y float64, z string) string {
+		return fmt.Sprintf("%s%d:%v", z, x, y)
+	})
+	assert.NoError(t, err)
+	strs, _ := labels.ToSliceExn()
+	assert.Equal(t, []string{"x1:0.5", "", "z3:1.5"}, strs)
+
+	short := series.NewSeriesTFromTSlice("s", []bool{true}, nil)
+	_, err = series.Zip2(a, short, func(int64, bool) bool { return true })
+	assert.True(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+	_, err = series.Zip3(a, b, short, func(int64, float64, bool) bool { return true })
+	assert.True(t, errors.Is(err, mangoerr.ErrShapeMismatch))
+
+	wrong := series.NewSeriesTFromArray[bool]("w", c.Chunks()[0])
+	_, err = series.Zip2(a, wrong, func(int64, bool) bool { return true })
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+	_, err = series.Zip3(a, b, wrong, func(int64, float64, bool) bool { return true })
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+}
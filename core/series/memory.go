@@ -0,0 +1,57 @@
+package series
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v12/arrow/compute"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+)
+
+// defaultAllocator holds the allocator set by SetDefaultAllocator, if any.
+var defaultAllocator atomic.Pointer[memory.Allocator]
+
+// DefaultAllocator returns the allocator of the arrays created by mango,
+// which is memory.DefaultAllocator unless it was replaced with SetDefaultAllocator.
+func DefaultAllocator() memory.Allocator {
+	if mem := defaultAllocator.Load(); mem != nil {
+		return *mem
+	}
+	return memory.DefaultAllocator
+}
+
+// SetDefaultAllocator replaces the allocator of the arrays created by mango, such as a pooled
+// or a memory-mapped allocator for big jobs, or a memory.CheckedAllocator to find leaks in tests.
+// A nil allocator restores memory.DefaultAllocator.
+// Arrays keep the allocator they were created with, so it can be replaced at any time.
+func SetDefaultAllocator(mem memory.Allocator) {
+	if mem == nil {
+		defaultAllocator.Store(nil)
+		return
+	}
+	defaultAllocator.Store(&mem)
+}
+
+// computeContext returns the context of arrow compute functions, which allocates with DefaultAllocator.
+func computeContext() context.Context {
+	return compute.WithAllocator(context.Background(), DefaultAllocator())
+}
+
+// Retain increases the reference count of the data of the Series.
+// Every Series returned by mango operations holds its own reference, so Retain is only needed
+// to keep using a Series sharing the reference of another, like those returned by Alias, Copy,
+// or DataFrame.Column, after the other is released.
+func (s *Series) Retain() {
+	if s.ca != nil {
+		s.ca.Retain()
+	}
+}
+
+// Release decreases the reference count of the data of the Series,
+// freeing its memory when no other Series hold a reference to it.
+// The Series must not be used after it is released.
+func (s *Series) Release() {
+	if s.ca != nil {
+		s.ca.Release()
+	}
+}
@@ -0,0 +1,69 @@
+package series
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kstremick/mango/core/internal/parallel"
+	"github.com/kstremick/mango/core/primitive"
+)
+
+// ParallelOptions configures the operations that run over several goroutines.
+// The zero value uses GOMAXPROCS workers.
+type ParallelOptions struct {
+	// Workers is the number of goroutines. It defaults to GOMAXPROCS when zero or less.
+	Workers int
+}
+
+// MapContext is like Map, but calls fn from several goroutines, and stops at the first error.
+// Values are split into contiguous ranges, so the result keeps the order of s.
+// If fn fails, the returned error is a *mangoerr.RowError for the first failing value.
+// If ctx is cancelled, MapContext stops and returns the error of ctx.
+func MapContext[T, U primitive.Primitive](ctx context.Context, s SeriesT[T], fn func(T) (U, error), opts ParallelOptions) (SeriesT[U], error) {
+	return MapOptionalContext(ctx, s, func(v primitive.Optional[T]) (primitive.Optional[U], error) {
+		if !v.Valid {
+			return primitive.None[U](), nil
+		}
+		u, err := fn(v.Value)
+		return primitive.Some(u), err
+	}, opts)
+}
+
+// MapOptionalContext is like MapOptional, but calls fn from several goroutines, see MapContext.
+func MapOptionalContext[T, U primitive.Primitive](ctx context.Context, s SeriesT[T], fn func(primitive.Optional[T]) (primitive.Optional[U], error), opts ParallelOptions) (SeriesT[U], error) {
+	vals := make([]U, s.Len())
+	valid := make([]bool, s.Len())
+	// The slices of every range are released once all ranges are done.
+	var mu sync.Mutex
+	var slices []*SeriesT[T]
+	defer func() {
+		for _, sliced := range slices {
+			sliced.Release()
+		}
+	}()
+	err := parallel.ForEach(ctx, s.Len(), opts.Workers, func(start, end int) func(int) error {
+		sliced, err := s.Slice(int64(start), int64(end-start))
+		if err != nil {
+			return func(int) error { return err }
+		}
+		mu.Lock()
+		slices = append(slices, &sliced)
+		mu.Unlock()
+		it := sliced.Iter()
+		return func(i int) error {
+			if !it.Next() {
+				return it.Err()
+			}
+			v, err := fn(it.Value())
+			if err != nil {
+				return err
+			}
+			vals[i], valid[i] = v.Value, v.Valid
+			return nil
+		}
+	})
+	if err != nil {
+		return SeriesT[U]{}, err
+	}
+	return SeriesT[U]{Series: newSeriesT(s.Name, vals, valid)}, nil
+}
@@ -0,0 +1,77 @@
+package series_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestMapContext(t *testing.T) {
+	n := 10000
+	vals := make([]int64, n)
+	valid := make([]bool, n)
+	for i := range vals {
+		vals[i] = int64(i)
+		valid[i] = i%7 != 0
+	}
+	s := series.NewSeriesTFromTSlice("a", vals, valid)
+
+	type testCase struct {
+		name    string
+		workers int
+	}
+	for _, tc := range []testCase{
+		{name: "default", workers: 0},
+		{name: "one worker", workers: 1},
+		{name: "many workers", workers: 16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			opts := series.ParallelOptions{Workers: tc.workers}
+
+			strs, err := series.MapContext(ctx, s, func(v int64) (string, error) {
+				return fmt.Sprint(v), nil
+			}, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, "a", strs.Name)
+			got, gotValid := strs.ToSliceExn()
+			for i := range got {
+				assert.Equal(t, valid[i], gotValid[i])
+				if valid[i] {
+					assert.Equal(t, fmt.Sprint(i), got[i])
+				}
+			}
+
+			// Every value from 5000 fails, and the first of them is reported.
+			_, err = series.MapOptionalContext(ctx, s, func(v primitive.Optional[int64]) (primitive.Optional[int64], error) {
+				if v.Valid && v.Value >= 5000 {
+					return v, errors.New("failed")
+				}
+				return v, nil
+			}, opts)
+			var rowErr *mangoerr.RowError
+			assert.That(t, errors.As(err, &rowErr))
+			assert.Equal(t, 5000, rowErr.Row)
+		})
+	}
+}
+
+func TestMapContextCancel(t *testing.T) {
+	s := series.NewSeriesTFromTSlice("a", make([]int64, 1000), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := series.MapContext(ctx, s, func(v int64) (int64, error) {
+		calls++
+		cancel()
+		return v, nil
+	}, series.ParallelOptions{Workers: 1})
+	assert.That(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,392 @@
+package series
+
+// Inspired by https://pola-rs.github.io/polars/py-polars/html/reference/series/computation.html
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// Rolling is a moving window over a Series.
+// Create one with Series.Rolling or Series.RollingBy, then call an aggregation on it.
+type Rolling struct {
+	s          Series
+	windowSize int
+	minPeriods int
+	center     bool
+	weights    []float64
+
+	// by and period are only set for temporal windows.
+	by     []int64
+	period time.Duration
+}
+
+// Rolling returns a fixed-size moving window over the Series.
+// The window at index i covers the windowSize values ending at i,
+// or the windowSize values around i if center is true.
+// A window with fewer than minPeriods non-null values results in a null.
+// If minPeriods is zero or negative, it defaults to windowSize.
+func (s *Series) Rolling(windowSize, minPeriods int, center bool) (*Rolling, error) {
+	if windowSize <= 0 {
+		return nil, fmt.Errorf("window size must be positive, got %d", windowSize)
+	}
+	if minPeriods <= 0 {
+		minPeriods = windowSize
+	}
+	if minPeriods > windowSize {
+		return nil, fmt.Errorf("min periods %d is greater than the window size %d", minPeriods, windowSize)
+	}
+	return &Rolling{
+		s:          *s,
+		windowSize: windowSize,
+		minPeriods: minPeriods,
+		center:     center,
+	}, nil
+}
+
+// RollingBy returns a temporal moving window over the Series.
+// The by Series holds the time of each row and must be sorted in ascending order without nulls.
+// It can be a timestamp Series, or an int64 Series holding nanoseconds.
+// The window at index i covers the rows whose time is in (by[i] - window, by[i]].
+// The window is a duration string like "7d", "2h30m" or "500ms".
+// Supported units are ns, us, ms, s, m, h, d and w.
+// If minPeriods is zero or negative, it defaults to 1.
+func (s *Series) RollingBy(by Series, window string, minPeriods int) (*Rolling, error) {
+	if by.Len() != s.Len() {
+		return nil, &mangoerr.ShapeMismatchError{What: "length of by", Expected: s.Len(), Actual: by.Len()}
+	}
+	period, err := parseWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	times, err := nanoseconds(&by)
+	if err != nil {
+		return nil, err
+	}
+	if minPeriods <= 0 {
+		minPeriods = 1
+	}
+	return &Rolling{
+		s:          *s,
+		minPeriods: minPeriods,
+		by:         times,
+		period:     period,
+	}, nil
+}
+
+// WithWeights returns a copy of the Rolling that multiplies each value by the weight at its position in the window.
+// There must be exactly one weight per window position.
+// Weights apply to Sum, Mean, Var and Std, and are not supported for temporal windows.
+func (r *Rolling) WithWeights(weights []float64) (*Rolling, error) {
+	if r.by != nil {
+		return nil, fmt.Errorf("weights are not supported for temporal windows")
+	}
+	if len(weights) != r.windowSize {
+		return nil, &mangoerr.ShapeMismatchError{What: "length of weights", Expected: r.windowSize, Actual: len(weights)}
+	}
+	ret := *r
+	ret.weights = weights
+	return &ret, nil
+}
+
+// Sum returns the rolling sum as a float64 Series.
+func (r *Rolling) Sum() (Series, error) {
+	return r.aggregate(func(vals, weights []float64) (float64, bool) {
+		sum := 0.0
+		for i, v := range vals {
+			sum += v * weights[i]
+		}
+		return sum, true
+	})
+}
+
+// Mean returns the rolling mean as a float64 Series.
+func (r *Rolling) Mean() (Series, error) {
+	return r.aggregate(func(vals, weights []float64) (float64, bool) {
+		sum, weightSum := 0.0, 0.0
+		for i, v := range vals {
+			sum += v * weights[i]
+			weightSum += weights[i]
+		}
+		if weightSum == 0 {
+			return 0, false
+		}
+		return sum / weightSum, true
+	})
+}
+
+// Var returns the rolling sample variance as a float64 Series.
+func (r *Rolling) Var() (Series, error) {
+	return r.aggregate(variance)
+}
+
+// Std returns the rolling sample standard deviation as a float64 Series.
+func (r *Rolling) Std() (Series, error) {
+	return r.aggregate(func(vals, weights []float64) (float64, bool) {
+		v, ok := variance(vals, weights)
+		return math.Sqrt(v), ok
+	})
+}
+
+// Min returns the rolling minimum as a float64 Series.
+func (r *Rolling) Min() (Series, error) {
+	if err := r.checkUnweighted("Min"); err != nil {
+		return Series{}, err
+	}
+	return r.aggregate(func(vals, _ []float64) (float64, bool) {
+		min := vals[0]
+		for _, v := range vals[1:] {
+			min = math.Min(min, v)
+		}
+		return min, true
+	})
+}
+
+// Max returns the rolling maximum as a float64 Series.
+func (r *Rolling) Max() (Series, error) {
+	if err := r.checkUnweighted("Max"); err != nil {
+		return Series{}, err
+	}
+	return r.aggregate(func(vals, _ []float64) (float64, bool) {
+		max := vals[0]
+		for _, v := range vals[1:] {
+			max = math.Max(max, v)
+		}
+		return max, true
+	})
+}
+
+// Median returns the rolling median as a float64 Series.
+func (r *Rolling) Median() (Series, error) {
+	return r.Quantile(0.5)
+}
+
+// Quantile returns the rolling quantile q as a float64 Series.
+// Values between two data points are linearly interpolated.
+func (r *Rolling) Quantile(q float64) (Series, error) {
+	if q < 0 || q > 1 {
+		return Series{}, fmt.Errorf("quantile must be between 0 and 1, got %v", q)
+	}
+	if err := r.checkUnweighted("Quantile"); err != nil {
+		return Series{}, err
+	}
+	return r.aggregate(func(vals, _ []float64) (float64, bool) {
+		return quantile(vals, q), true
+	})
+}
+
+// RollingApply applies a custom function over each window of the Rolling.
+// The function receives the non-null values of the window, and the result has the same type as the Series.
+func RollingApply[T primitive.Primitive](r *Rolling, fn func([]T) T) (Series, error) {
+	if err := r.checkUnweighted("RollingApply"); err != nil {
+		return Series{}, err
+	}
+	vals, valids, err := valuesT[T](&r.s)
+	if err != nil {
+		return Series{}, err
+	}
+	rets := make([]T, len(vals))
+	retValids := make([]bool, len(vals))
+	window := make([]T, 0, r.windowSize)
+	r.eachWindow(func(i, start, end int) {
+		window = window[:0]
+		for j := start; j < end; j++ {
+			if valids[j] {
+				window = append(window, vals[j])
+			}
+		}
+		if len(window) >= r.minPeriods {
+			rets[i] = fn(window)
+			retValids[i] = true
+		}
+	})
+	return newSeriesT(r.s.Name, rets, retValids), nil
+}
+
+// aggregate applies fn over the non-null values of each window.
+// fn receives the weight of every value, which is 1 for unweighted windows.
+// If fn returns false, the result for that window is null.
+func (r *Rolling) aggregate(fn func(vals, weights []float64) (float64, bool)) (Series, error) {
+	vals, valids, err := r.s.float64Values()
+	if err != nil {
+		return Series{}, err
+	}
+	rets := make([]float64, len(vals))
+	retValids := make([]bool, len(vals))
+	window := make([]float64, 0, r.windowSize)
+	weights := make([]float64, 0, r.windowSize)
+	r.eachWindow(func(i, start, end int) {
+		window, weights = window[:0], weights[:0]
+		for j := start; j < end; j++ {
+			if !valids[j] {
+				continue
+			}
+			window = append(window, vals[j])
+			weights = append(weights, r.weight(i, j))
+		}
+		if len(window) >= r.minPeriods && len(window) > 0 {
+			rets[i], retValids[i] = fn(window, weights)
+		}
+	})
+	return newSeriesT(r.s.Name, rets, retValids), nil
+}
+
+// eachWindow calls fn with the bounds [start, end) of the window at every index.
+func (r *Rolling) eachWindow(fn func(i, start, end int)) {
+	n := r.s.Len()
+	start := 0
+	for i := 0; i < n; i++ {
+		if r.by != nil {
+			for r.by[start] <= r.by[i]-int64(r.period) {
+				start++
+			}
+			fn(i, start, i+1)
+			continue
+		}
+		lo := r.windowStart(i)
+		hi := lo + r.windowSize
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > n {
+			hi = n
+		}
+		fn(i, lo, hi)
+	}
+}
+
+// windowStart returns the first index of the fixed-size window at i.
+// It may be negative at the start of the Series.
+func (r *Rolling) windowStart(i int) int {
+	if r.center {
+		return i - r.windowSize/2
+	}
+	return i - r.windowSize + 1
+}
+
+// weight returns the weight of index j in the window at index i.
+func (r *Rolling) weight(i, j int) float64 {
+	if r.weights == nil {
+		return 1
+	}
+	return r.weights[j-r.windowStart(i)]
+}
+
+func (r *Rolling) checkUnweighted(op string) error {
+	if r.weights != nil {
+		return fmt.Errorf("%s does not support weighted windows", op)
+	}
+	return nil
+}
+
+// variance computes the sample variance using reliability weights.
+// With unit weights this is the usual variance with one degree of freedom.
+func variance(vals, weights []float64) (float64, bool) {
+	sum, weightSum, weightSqSum := 0.0, 0.0, 0.0
+	for i, v := range vals {
+		sum += v * weights[i]
+		weightSum += weights[i]
+		weightSqSum += weights[i] * weights[i]
+	}
+	if weightSum == 0 {
+		return 0, false
+	}
+	denom := weightSum - weightSqSum/weightSum
+	if denom <= 0 {
+		return 0, false
+	}
+	mean := sum / weightSum
+	sqDiffs := 0.0
+	for i, v := range vals {
+		sqDiffs += weights[i] * (v - mean) * (v - mean)
+	}
+	return sqDiffs / denom, true
+}
+
+// quantile computes the quantile q of vals, linearly interpolating between data points.
+func quantile(vals []float64, q float64) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// nanoseconds returns the values of a timestamp or int64 Series as nanoseconds.
+// It returns an error if the Series has nulls or is not sorted.
+func nanoseconds(s *Series) ([]int64, error) {
+	if s.NullN() > 0 {
+		return nil, fmt.Errorf("series %s must not contain nulls", s.Name)
+	}
+	ret := make([]int64, 0, s.Len())
+	for _, chunk := range s.Chunks() {
+		switch chunk := chunk.(type) {
+		case *array.Timestamp:
+			multiplier := int64(chunk.DataType().(*arrow.TimestampType).Unit.Multiplier())
+			for _, v := range chunk.TimestampValues() {
+				ret = append(ret, int64(v)*multiplier)
+			}
+		case *array.Int64:
+			ret = append(ret, chunk.Int64Values()...)
+		default:
+			return nil, fmt.Errorf("series %s: %w", s.Name, &mangoerr.TypeMismatchError{Expected: "timestamp or int64", Actual: s.DataType()})
+		}
+	}
+	for i := 1; i < len(ret); i++ {
+		if ret[i] < ret[i-1] {
+			return nil, fmt.Errorf("series %s must be sorted in ascending order", s.Name)
+		}
+	}
+	return ret, nil
+}
+
+var windowUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// parseWindow parses a window duration string like "7d" or "1h30m".
+func parseWindow(window string) (time.Duration, error) {
+	var ret time.Duration
+	rest := window
+	for len(rest) > 0 {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		j := i
+		for j < len(rest) && (rest[j] < '0' || rest[j] > '9') {
+			j++
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", window)
+		}
+		unit, ok := windowUnits[rest[i:j]]
+		if !ok {
+			return 0, fmt.Errorf("invalid unit %q in window %q", rest[i:j], window)
+		}
+		ret += time.Duration(n) * unit
+		rest = rest[j:]
+	}
+	if ret <= 0 {
+		return 0, fmt.Errorf("window must be positive, got %q", window)
+	}
+	return ret, nil
+}
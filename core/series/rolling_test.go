@@ -0,0 +1,171 @@
+package series_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/zeebo/assert"
+)
+
+// values returns the values of a Series, with nulls as nil.
+func values(s series.Series) []interface{} {
+	ret := make([]interface{}, s.Len())
+	for i := range ret {
+		v := s.ValueExn(i)
+		if v.Valid {
+			ret[i] = v.Value
+		}
+	}
+	return ret
+}
+
+func TestRollingAggregations(t *testing.T) {
+	ser := series.NewSeries("test", []int64{1, 2, 3, 4, 5})
+	r, err := ser.Rolling(3, 0, false)
+	assert.NoError(t, err)
+
+	type testCase struct {
+		name     string
+		fn       func() (series.Series, error)
+		expected []interface{}
+	}
+	testCases := []testCase{
+		{name: "sum", fn: r.Sum, expected: []interface{}{nil, nil, 6.0, 9.0, 12.0}},
+		{name: "mean", fn: r.Mean, expected: []interface{}{nil, nil, 2.0, 3.0, 4.0}},
+		{name: "min", fn: r.Min, expected: []interface{}{nil, nil, 1.0, 2.0, 3.0}},
+		{name: "max", fn: r.Max, expected: []interface{}{nil, nil, 3.0, 4.0, 5.0}},
+		{name: "var", fn: r.Var, expected: []interface{}{nil, nil, 1.0, 1.0, 1.0}},
+		{name: "std", fn: r.Std, expected: []interface{}{nil, nil, 1.0, 1.0, 1.0}},
+		{name: "median", fn: r.Median, expected: []interface{}{nil, nil, 2.0, 3.0, 4.0}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.fn()
+			assert.NoError(t, err)
+			assert.Equal(t, "test", res.Name)
+			assert.Equal(t, arrow.FLOAT64, res.Type())
+			assert.DeepEqual(t, tc.expected, values(res))
+		})
+	}
+
+	q, err := r.Quantile(0.25)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{nil, nil, 1.5, 2.5, 3.5}, values(q))
+
+	_, err = r.Quantile(2)
+	assert.Error(t, err)
+}
+
+func TestRollingMinPeriodsAndCenter(t *testing.T) {
+	ser := series.NewSeries("test", []interface{}{1.0, primitive.Null{}, 3.0, 4.0})
+
+	r, err := ser.Rolling(2, 1, false)
+	assert.NoError(t, err)
+	sum, err := r.Sum()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{1.0, 1.0, 3.0, 7.0}, values(sum))
+
+	r, err = ser.Rolling(3, 1, true)
+	assert.NoError(t, err)
+	sum, err = r.Sum()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{1.0, 4.0, 7.0, 7.0}, values(sum))
+
+	_, err = ser.Rolling(2, 3, false)
+	assert.Error(t, err)
+	_, err = ser.Rolling(0, 0, false)
+	assert.Error(t, err)
+
+	strs := series.NewSeries("test", []string{"a", "b"})
+	r, err = strs.Rolling(1, 0, false)
+	assert.NoError(t, err)
+	_, err = r.Sum()
+	assert.Error(t, err)
+}
+
+func TestRollingWeights(t *testing.T) {
+	ser := series.NewSeries("test", []float64{1, 2, 3, 4})
+	r, err := ser.Rolling(2, 0, false)
+	assert.NoError(t, err)
+	weighted, err := r.WithWeights([]float64{1, 3})
+	assert.NoError(t, err)
+
+	sum, err := weighted.Sum()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{nil, 7.0, 11.0, 15.0}, values(sum))
+
+	mean, err := weighted.Mean()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{nil, 1.75, 2.75, 3.75}, values(mean))
+
+	_, err = weighted.Min()
+	assert.Error(t, err)
+	_, err = r.WithWeights([]float64{1})
+	assert.Error(t, err)
+}
+
+func TestRollingApply(t *testing.T) {
+	ser := series.NewSeries("test", []int64{1, 2, 3, 4})
+	r, err := ser.Rolling(2, 0, false)
+	assert.NoError(t, err)
+
+	res, err := series.RollingApply(r, func(window []int64) int64 {
+		return window[len(window)-1] - window[0]
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, arrow.INT64, res.Type())
+	assert.DeepEqual(t, []interface{}{nil, int64(1), int64(1), int64(1)}, values(res))
+
+	_, err = series.RollingApply(r, func(window []string) string { return "" })
+	assert.Error(t, err)
+}
+
+func TestRollingBy(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := []time.Time{start, start.Add(day), start.Add(5 * day), start.Add(7 * day), start.Add(8 * day)}
+
+	b := array.NewTimestampBuilder(memory.NewGoAllocator(), &arrow.TimestampType{Unit: arrow.Millisecond})
+	defer b.Release()
+	for _, ts := range times {
+		b.Append(arrow.Timestamp(ts.UnixMilli()))
+	}
+	by := series.NewSeriesFromArray("time", b.NewArray())
+	assert.Equal(t, times[2], by.ValueExn(2).Value)
+
+	ser := series.NewSeries("test", []float64{1, 2, 3, 4, 5})
+	r, err := ser.RollingBy(by, "7d", 0)
+	assert.NoError(t, err)
+	sum, err := r.Sum()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{1.0, 3.0, 6.0, 9.0, 12.0}, values(sum))
+
+	r, err = ser.RollingBy(by, "1d12h", 2)
+	assert.NoError(t, err)
+	mean, err := r.Mean()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{nil, 1.5, nil, nil, 4.5}, values(mean))
+
+	_, err = ser.RollingBy(by, "7x", 0)
+	assert.Error(t, err)
+
+	unsorted := series.NewSeries("time", []int64{2, 1, 3, 4, 5})
+	_, err = ser.RollingBy(unsorted, "1ns", 0)
+	assert.Error(t, err)
+}
+
+func TestRollingStdIsSampleStd(t *testing.T) {
+	ser := series.NewSeries("test", []float64{2, 4, 4, 4, 5, 5, 7, 9})
+	r, err := ser.Rolling(8, 0, false)
+	assert.NoError(t, err)
+	std, err := r.Std()
+	assert.NoError(t, err)
+	assert.Equal(t, math.Sqrt(32.0/7.0), std.ValueExn(7).Value)
+}
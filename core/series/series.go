@@ -3,15 +3,17 @@ package series
 // Inspired by https://pola-rs.github.io/polars/polars/series/trait.SeriesTrait.html
 
 import (
+	"errors"
 	"fmt"
-
-	"github.com/kstremick/mango/core/primitive"
+	"sort"
 
 	"github.com/kstremick/mango/core/chunked"
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
 
 	"github.com/apache/arrow/go/v12/arrow"
 	"github.com/apache/arrow/go/v12/arrow/array"
-	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/arrow/compute"
 )
 
 type InterfaceBase struct {
@@ -22,21 +24,31 @@ type InterfaceBase struct {
 type Series struct {
 	Name string
 	ca   *arrow.Chunked
+	// offsets holds the index of the first value of every chunk, followed by the length of the Series.
+	// It must be updated with setChunked whenever ca changes.
+	offsets []int
 }
 
-// NewSeries creates a new Series from a chunked array.
+// NewSeriesFromArray creates a new Series from an array.
+// The Series retains arr, so the caller still has to release its own reference.
 func NewSeriesFromArray(name string, arr arrow.Array) Series {
-	return Series{
-		Name: name,
-		ca:   arrow.NewChunked(arr.DataType(), []arrow.Array{arr}),
-	}
+	return NewSeriesFromChunked(name, arrow.NewChunked(arr.DataType(), []arrow.Array{arr}))
 }
 
 // NewSeriesFromChunked creates a new Series from a chunked array.
+// The Series takes ownership of the reference to ca, and releases it in Release.
 func NewSeriesFromChunked(name string, ca *arrow.Chunked) Series {
-	return Series{
-		Name: name,
-		ca:   ca,
+	s := Series{Name: name}
+	s.setChunked(ca)
+	return s
+}
+
+// setChunked replaces the data of the Series, and computes the offsets of its chunks.
+func (s *Series) setChunked(ca *arrow.Chunked) {
+	s.ca = ca
+	s.offsets = make([]int, len(ca.Chunks())+1)
+	for i, chunk := range ca.Chunks() {
+		s.offsets[i+1] = s.offsets[i] + chunk.Len()
 	}
 }
 
@@ -47,85 +59,136 @@ func NewSeriesFromChunked(name string, ca *arrow.Chunked) Series {
 // If inferTypes is true, the type of the series will be inferred from the values in v.
 // Mango will attempt to parse your values -- for example, ["1", "2", "3"] will
 // be parsed into a series of type int64 if inferTypes is true.
+// It panics on errors, see NewSeriesFromSliceChecked.
 func NewSeriesFromSlice[T any](name string, valsAsT []T, valid []bool, inferTypes bool) Series {
-	memory := memory.NewGoAllocator()
-	var ret arrow.Array
-	var typeToConvertTo arrow.DataType
-	var err error
+	s, err := NewSeriesFromSliceChecked(name, valsAsT, valid, inferTypes)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
 
+// NewSeriesFromSliceChecked is like NewSeriesFromSlice, but returns an error
+// if the type of the values cannot be found or the valid slice has the wrong length.
+// An empty slice of int64, float64, bool or string makes an empty Series of that type,
+// see NewEmptySeries for other types.
+func NewSeriesFromSliceChecked[T any](name string, valsAsT []T, valid []bool, inferTypes bool) (Series, error) {
+	if len(valid) > 0 && len(valid) != len(valsAsT) {
+		return Series{}, &mangoerr.ShapeMismatchError{What: "length of valid", Expected: len(valsAsT), Actual: len(valid)}
+	}
 	vals := make([]interface{}, len(valsAsT))
 	for i, v := range valsAsT {
 		vals[i] = v
 	}
 
-	if !inferTypes {
+	var typeToConvertTo arrow.DataType
+	var err error
+	if len(vals) == 0 {
+		typeToConvertTo, err = primitive.ToArrowDatatype(*new(T))
+		if err != nil {
+			return Series{}, fmt.Errorf("%w: cannot find the type of an empty %T, use NewEmptySeries", mangoerr.ErrTypeMismatch, valsAsT)
+		}
+	} else if !inferTypes {
 		typeToConvertTo, err = primitive.ExtractDatatype(vals)
 	} else {
 		typeToConvertTo, err = primitive.InferDatatype(vals)
 	}
 	if err != nil {
-		panic(err)
+		return Series{}, fmt.Errorf("%w: %v", mangoerr.ErrTypeMismatch, err)
 	}
 
 	switch typeToConvertTo.ID() {
 	case arrow.STRING:
-		b := array.NewStringBuilder(memory)
-		defer b.Release()
-		b.AppendValues(primitive.CastListT[string](vals, valid))
-		ret = b.NewStringArray()
+		casted, valids := primitive.CastListT[string](vals, valid)
+		return newSeriesT(name, casted, valids), nil
 	case arrow.FLOAT64:
-		b := array.NewFloat64Builder(memory)
-		defer b.Release()
-		b.AppendValues(primitive.CastListT[float64](vals, valid))
-		ret = b.NewFloat64Array()
+		casted, valids := primitive.CastListT[float64](vals, valid)
+		return newSeriesT(name, casted, valids), nil
 	case arrow.BOOL:
-		b := array.NewBooleanBuilder(memory)
-		defer b.Release()
-		b.AppendValues(primitive.CastListT[bool](vals, valid))
-		ret = b.NewBooleanArray()
+		casted, valids := primitive.CastListT[bool](vals, valid)
+		return newSeriesT(name, casted, valids), nil
 	case arrow.INT64:
-		b := array.NewInt64Builder(memory)
-		defer b.Release()
-		b.AppendValues(primitive.CastListT[int64](vals, valid))
-		ret = b.NewInt64Array()
-
+		casted, valids := primitive.CastListT[int64](vals, valid)
+		return newSeriesT(name, casted, valids), nil
 	}
-	return NewSeriesFromArray(name, ret)
+	return Series{}, &mangoerr.TypeMismatchError{Expected: "string, float64, bool or int64", Actual: typeToConvertTo}
+}
+
+// NewEmptySeries creates a new Series of the given type without values.
+func NewEmptySeries(name string, dtype arrow.DataType) Series {
+	b := array.NewBuilder(DefaultAllocator(), dtype)
+	defer b.Release()
+	arr := b.NewArray()
+	defer arr.Release()
+	return NewSeriesFromArray(name, arr)
 }
 
 // NewSeriesFromValue creates a new Series from a single value.
+// It panics on errors, see NewSeriesFromValueChecked.
 func NewSeriesFromValue(name string, val interface{}) Series {
 	return NewSeriesFromSlice(name, []interface{}{val}, nil, false)
 }
 
+// NewSeriesFromValueChecked creates a new Series from a single value,
+// returning an error if its type is not supported.
+func NewSeriesFromValueChecked(name string, val interface{}) (Series, error) {
+	return NewSeriesFromSliceChecked(name, []interface{}{val}, nil, false)
+}
+
+// NewSeriesFromArrayT creates a new Series from a slice of T.
+// It panics on errors, see NewSeriesFromArrayTChecked.
 func NewSeriesFromArrayT[T any](name string, arr []T) Series {
-	genericArr := make([]interface{}, len(arr))
-	for i, v := range arr {
-		genericArr[i] = v
+	s, err := NewSeriesFromArrayTChecked(name, arr)
+	if err != nil {
+		panic(err)
 	}
-	return NewSeriesFromSlice(name, genericArr, nil, false)
+	return s
+}
+
+// NewSeriesFromArrayTChecked creates a new Series from a slice of T,
+// returning an error if T is not supported.
+func NewSeriesFromArrayTChecked[T any](name string, arr []T) (Series, error) {
+	return NewSeriesFromSliceChecked(name, arr, nil, false)
 }
 
 // NewSeries creates a new series from arbitrary data, matching on the structure of that data.
 // Matches slice, array, and single value.
+// It panics on errors, see NewSeriesChecked.
 func NewSeries(name string, data interface{}) Series {
+	s, err := NewSeriesChecked(name, data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewSeriesChecked is like NewSeries, but returns an error if the data is not supported.
+func NewSeriesChecked(name string, data interface{}) (Series, error) {
 	switch data := data.(type) {
 	case []primitive.Optional[interface{}]:
-		return NewSeriesFromSlice(name, data, nil, true)
+		vals := make([]interface{}, len(data))
+		for i, v := range data {
+			if v.Valid {
+				vals[i] = v.Value
+			} else {
+				vals[i] = primitive.Null{}
+			}
+		}
+		return NewSeriesFromSliceChecked(name, vals, nil, true)
 	case []interface{}:
-		return NewSeriesFromSlice(name, data, nil, true)
+		return NewSeriesFromSliceChecked(name, data, nil, true)
 	case []int64:
-		return NewSeriesFromArrayT(name, data)
+		return NewSeriesFromArrayTChecked(name, data)
 	case []float64:
-		return NewSeriesFromArrayT(name, data)
+		return NewSeriesFromArrayTChecked(name, data)
 	case []bool:
-		return NewSeriesFromArrayT(name, data)
+		return NewSeriesFromArrayTChecked(name, data)
 	case []string:
-		return NewSeriesFromArrayT(name, data)
+		return NewSeriesFromArrayTChecked(name, data)
 	case arrow.Array:
-		return NewSeriesFromArray(name, data)
+		return NewSeriesFromArray(name, data), nil
 	default:
-		return NewSeriesFromValue(name, data)
+		return NewSeriesFromValueChecked(name, data)
 	}
 }
 
@@ -151,19 +214,43 @@ func (s *Series) Chunks() []arrow.Array {
 }
 
 // Alias returns a new Series with the same data, but a different name.
+// The Series share the same reference to the data, see Retain.
 func (s Series) Alias(name string) Series {
-	return Series{
-		Name: name,
-		ca:   s.ca,
-	}
+	s.Name = name
+	return s
 }
 
 // Copy returns a cheap copy of the Series.
+// The Series share the same reference to the data, see Retain.
 func (s *Series) Copy() Series {
-	return Series{
-		Name: s.Name,
-		ca:   s.ca,
+	return *s
+}
+
+// Append returns a new Series with the values of other added after the values of s.
+// The chunks of other are appended to the chunks of s, so this operation does not copy the data.
+func (s *Series) Append(other Series) (Series, error) {
+	if !arrow.TypeEqual(s.DataType(), other.DataType()) {
+		return Series{}, fmt.Errorf("cannot append series: %w", &mangoerr.TypeMismatchError{Expected: s.DataType().String(), Actual: other.DataType()})
 	}
+	chunks := append(append([]arrow.Array{}, s.Chunks()...), other.Chunks()...)
+	return NewSeriesFromChunked(s.Name, arrow.NewChunked(s.DataType(), chunks)), nil
+}
+
+// Extend adds the values of other after the values of s, in place.
+// Unlike Append, the values are copied into a single contiguous chunk.
+// The reference to the previous data is released, so copies of s are left empty unless retained, see Rechunk.
+func (s *Series) Extend(other Series) error {
+	appended, err := s.Append(other)
+	if err != nil {
+		return err
+	}
+	if err := appended.Rechunk(); err != nil {
+		appended.Release()
+		return err
+	}
+	s.ca.Release()
+	s.setChunked(appended.ca)
+	return nil
 }
 
 // Slice returns a zero-copy slice of the Series.
@@ -173,64 +260,70 @@ func (s *Series) Slice(offset, length int64) (Series, error) {
 	if offset < 0 {
 		offset = offset + int64(s.ca.Len())
 	}
+	if offset < 0 || length < 0 {
+		return Series{}, fmt.Errorf("%w: offset %d and length %d must fit in the Series of length %d", mangoerr.ErrOutOfBounds, offset, length, s.ca.Len())
+	}
 	if length > int64(s.ca.Len()) {
-		return Series{}, fmt.Errorf("length %d is greater than the length of the Series %d", length, s.ca.Len())
+		return Series{}, fmt.Errorf("%w: length %d is greater than the length of the Series %d", mangoerr.ErrOutOfBounds, length, s.ca.Len())
 	}
 	if length+offset > int64(s.ca.Len()) {
-		return Series{}, fmt.Errorf("length + offset %d is greater than the length of the Series %d", length+offset, s.ca.Len())
+		return Series{}, fmt.Errorf("%w: length + offset %d is greater than the length of the Series %d", mangoerr.ErrOutOfBounds, length+offset, s.ca.Len())
 	}
 	chunkSlice := array.NewChunkedSlice(s.ca, offset, offset+length)
-	return Series{Name: s.Name, ca: (chunkSlice)}, nil
+	return NewSeriesFromChunked(s.Name, chunkSlice), nil
 }
 
-// Filter filters by boolean mask. This operation clones data.
+// Filter filters by boolean mask. Null values in the mask are treated as false.
+// This operation copies the data.
 func (s *Series) Filter(mask *SeriesT[bool]) (Series, error) {
 	if mask.Len() != s.ca.Len() {
-		return Series{}, fmt.Errorf("length of mask %d is not equal to the length of the Series %d", mask.Len(), s.ca.Len())
+		return Series{}, &mangoerr.ShapeMismatchError{What: "length of mask", Expected: s.ca.Len(), Actual: mask.Len()}
 	}
 	if mask.DataType().ID() != arrow.BOOL {
-		return Series{}, fmt.Errorf("mask is not of type bool")
+		return Series{}, fmt.Errorf("mask: %w", &mangoerr.TypeMismatchError{Expected: "bool", Actual: mask.DataType()})
 	}
-	rets := make([]interface{}, mask.Len())
-	retsI := 0
-	for i := 0; i < mask.Len(); i++ {
-		isValid, err := mask.Value(i)
-		if err != nil {
-			return Series{}, err
-		}
-		// if v is Nill, value should be the default value, which is false.
-		if isValid.Value {
-			v, e := s.Value(i)
-			if e != nil {
-				return Series{}, err
-			}
-			rets[retsI] = v.Value
-			retsI++
-		}
+	values := compute.NewDatum(s.ca)
+	defer values.Release()
+	filter := compute.NewDatum(mask.ca)
+	defer filter.Release()
+
+	out, err := compute.Filter(computeContext(), values, filter, *compute.DefaultFilterOptions())
+	if err != nil {
+		return Series{}, err
 	}
-	return NewSeriesFromSlice(s.Name, rets, nil, false), nil
+	return seriesFromDatum(s.Name, out)
 }
 
-// Take by index. This operation copies the data.
+// Take by index. Null indices result in null values.
+// This operation copies the data.
 func (s *Series) Take(indices *SeriesT[int64]) (Series, error) {
-	if indices.Len() > s.ca.Len() {
-		return Series{}, fmt.Errorf("length of indices %d is greater than the length of the Series %d", indices.Len(), s.ca.Len())
-	}
-
-	rets := make([]interface{}, indices.Len())
-	valids := make([]bool, indices.Len())
-	for i := 0; i < indices.Len(); i++ {
-		optIndex, _ := indices.Value(i)
-		if optIndex.Valid {
-			value, isValid, err := s.ValueUnpacked(int(optIndex.Value))
-			if err != nil {
-				return Series{}, err
-			}
-			rets[i] = value
-			valids[i] = isValid
-		}
+	values := compute.NewDatum(s.ca)
+	defer values.Release()
+	idx := compute.NewDatum(indices.ca)
+	defer idx.Release()
+
+	out, err := compute.Take(computeContext(), *compute.DefaultTakeOptions(), values, idx)
+	if errors.Is(err, arrow.ErrIndex) {
+		return Series{}, fmt.Errorf("%w: %v", mangoerr.ErrOutOfBounds, err)
+	}
+	if err != nil {
+		return Series{}, err
+	}
+	return seriesFromDatum(s.Name, out)
+}
+
+// seriesFromDatum creates a new Series from the result of an arrow compute function.
+func seriesFromDatum(name string, d compute.Datum) (Series, error) {
+	switch d := d.(type) {
+	case *compute.ChunkedDatum:
+		return NewSeriesFromChunked(name, d.Value), nil
+	case *compute.ArrayDatum:
+		defer d.Release()
+		arr := d.MakeArray()
+		defer arr.Release()
+		return NewSeriesFromArray(name, arr), nil
 	}
-	return NewSeriesFromSlice(s.Name, rets, valids, false), nil
+	return Series{}, fmt.Errorf("unexpected result %s", d)
 }
 
 // Len returns the length of the Series.
@@ -253,37 +346,93 @@ func (s *Series) Type() arrow.Type {
 	return s.ca.DataType().ID()
 }
 
-// Rechunk aggregates all chunks to a contiguous array of memory.
-func (s *Series) Rechunk() {
-	if s.NumChunks() <= 1 {
-		return
+// Cast converts the Series to another arrow data type.
+// Returns an error if any value cannot be converted without loss, such as "a" to int64 or 1.5 to int64.
+func (s *Series) Cast(dtype arrow.DataType) (Series, error) {
+	if arrow.TypeEqual(s.DataType(), dtype) {
+		s.Retain()
+		return s.Copy(), nil
 	}
-	rets := make([]interface{}, 0, s.ca.Len())
-	valids := make([]bool, 0, s.ca.Len())
+	chunks := make([]arrow.Array, 0, s.NumChunks())
+	defer func() {
+		for _, chunk := range chunks {
+			chunk.Release()
+		}
+	}()
 	for _, chunk := range s.Chunks() {
-		chunkVals, chunkValids, err := chunked.ExtractChunk[string](chunk)
+		casted, err := compute.CastArray(computeContext(), chunk, compute.SafeCastOptions(dtype))
 		if err != nil {
-			panic(err)
+			return Series{}, fmt.Errorf("%w: cannot cast %s from %s to %s: %w", mangoerr.ErrTypeMismatch, s.Name, s.DataType(), dtype, err)
 		}
-		rets = append(rets, any(chunkVals).([]interface{})...)
-		valids = append(valids, chunkValids...)
+		chunks = append(chunks, casted)
+	}
+	return NewSeriesFromChunked(s.Name, arrow.NewChunked(dtype, chunks)), nil
+}
+
+// Rechunk aggregates all chunks to a contiguous array of memory in place, keeping the type of the Series.
+// This operation copies the data, unless the Series has a single chunk.
+//
+// The reference to the previous data is released. Copies of the Series sharing it without retaining it,
+// like plain copies (col := df.Series[0]) or those returned by Alias, Copy or DataFrame.Column,
+// are left empty unless they were retained first. Use Rechunked to leave the Series and its copies unchanged.
+func (s *Series) Rechunk() error {
+	if s.NumChunks() <= 1 {
+		return nil
+	}
+	rechunked, err := s.Rechunked()
+	if err != nil {
+		return err
 	}
-	newSeries := NewSeriesFromSlice(s.Name, rets, valids, false)
 	s.ca.Release()
-	s.ca = newSeries.ca
+	s.setChunked(rechunked.ca)
+	return nil
+}
+
+// Rechunked returns a new Series with the values of s in a contiguous array of memory, leaving s unchanged.
+// This operation copies the data, unless the Series has a single chunk.
+func (s *Series) Rechunked() (Series, error) {
+	if s.NumChunks() <= 1 {
+		ret := *s
+		ret.Retain()
+		return ret, nil
+	}
+	arr, err := array.Concatenate(s.Chunks(), DefaultAllocator())
+	if err != nil {
+		return Series{}, err
+	}
+	defer arr.Release()
+	return NewSeriesFromChunked(s.Name, arrow.NewChunked(s.DataType(), []arrow.Array{arr})), nil
+}
+
+// RechunkExn is like Rechunk, but panics on errors.
+func (s *Series) RechunkExn() {
+	if err := s.Rechunk(); err != nil {
+		panic(err)
+	}
 }
 
 // TakeEvery takes every nth element as a new Series.
-func (s *Series) TakeEvery(n int) Series {
+// Returns an error if n is not positive.
+func (s *Series) TakeEvery(n int) (Series, error) {
+	if n <= 0 {
+		return Series{}, fmt.Errorf("%w: n must be positive, got %d", mangoerr.ErrOutOfBounds, n)
+	}
 	if n == 1 {
-		return *s
+		s.Retain()
+		return *s, nil
 	}
 	bools := make([]bool, s.Len())
 	for i := 0; i < s.Len(); i += n {
 		bools[i] = true
 	}
 	ser := NewSeriesTFromTSlice("", bools, nil)
-	ret, err := s.Filter(&ser)
+	defer ser.Release()
+	return s.Filter(&ser)
+}
+
+// TakeEveryExn is like TakeEvery, but panics on errors.
+func (s *Series) TakeEveryExn(n int) Series {
+	ret, err := s.TakeEvery(n)
 	if err != nil {
 		panic(err)
 	}
@@ -291,48 +440,40 @@ func (s *Series) TakeEvery(n int) Series {
 }
 
 // ResolveIndex returns the index of the chunk, and the index within that chunk.
+// The index i must be within the bounds of the Series.
+// It binary searches the offsets of the chunks, so it takes O(log(chunks)) time.
 func (s *Series) ResolveIndex(i int) (int, int) {
-	chunkLengths := s.ChunkLengths()
-	chunkIndex := 0
-	for i >= chunkLengths[chunkIndex] {
-		if i-chunkLengths[chunkIndex] < 0 {
-			break
-		}
-		i -= chunkLengths[chunkIndex]
-		chunkIndex++
-	}
-	return chunkIndex, i
+	// Find the last chunk starting at or before i, skipping empty chunks.
+	chunkIndex := sort.Search(len(s.offsets)-1, func(c int) bool { return s.offsets[c+1] > i })
+	return chunkIndex, i - s.offsets[chunkIndex]
 }
 
-// valueExnHelper returns the value at index i, wrapped in a primitive.Optional
-// It does not returns errors -- it panics on them
-// See ValueExn for the function that returns the same thing, typed as primitive.Optional.
-func (s *Series) valueExnHelper(i int) interface{} {
-	chunkIndex, i := s.ResolveIndex(i)
-
-	chunk := s.Chunks()[chunkIndex]
-	if chunk.IsNull(i) {
-		return primitive.None[interface{}]()
-	}
-	extractValueFn, err := chunked.ExtractValueFn(chunk)
+// ValueExn returns the value at index i, wrapped in a primitive.Optional.
+// It panics on errors, see Value.
+func (s *Series) ValueExn(i int) primitive.Optional[interface{}] {
+	v, err := s.Value(i)
 	if err != nil {
 		panic(err)
 	}
-	return primitive.Some(extractValueFn(i))
-}
-
-func (s *Series) ValueExn(i int) primitive.Optional[interface{}] {
-	v := s.valueExnHelper(i)
-	return v.(primitive.Optional[interface{}])
+	return v
 }
 
 // Value returns the value at index i, wrapped in a primitive.Optional
 // If the value is null, the second return value is false.
 func (s *Series) Value(i int) (primitive.Optional[interface{}], error) {
-	if i >= s.Len() {
-		return primitive.None[interface{}](), fmt.Errorf("index out of range")
+	if i < 0 || i >= s.Len() {
+		return primitive.None[interface{}](), &mangoerr.OutOfBoundsError{Index: i, Length: s.Len()}
+	}
+	chunkIndex, i := s.ResolveIndex(i)
+	chunk := s.Chunks()[chunkIndex]
+	if chunk.IsNull(i) {
+		return primitive.None[interface{}](), nil
+	}
+	extractValueFn, err := chunked.ExtractValueFn(chunk)
+	if err != nil {
+		return primitive.None[interface{}](), err
 	}
-	return s.ValueExn(i), nil
+	return primitive.Some(extractValueFn(i)), nil
 }
 
 // ValueUnpacked returns the value at index i, without wrapping it in a primitive.Optional
@@ -352,8 +493,8 @@ func (s *Series) ValueUnpacked(i int) (interface{}, bool, error) {
 // Head returns the first n elements of the Series, wrapped in a primitive.Optional.
 // The third element is an error if the user's request is invalid or misformatted.
 func (s *Series) Head(n int) ([]interface{}, error) {
-	if n > s.ca.Len() {
-		return nil, fmt.Errorf("n %d is greater than the length of the Series %d", n, s.ca.Len())
+	if n < 0 || n > s.ca.Len() {
+		return nil, fmt.Errorf("%w: n %d must be between 0 and the length of the Series %d", mangoerr.ErrOutOfBounds, n, s.ca.Len())
 	}
 	ret := make([]interface{}, n)
 	for i := 0; i < n; i++ {
@@ -364,25 +505,29 @@ func (s *Series) Head(n int) ([]interface{}, error) {
 	return ret, nil
 }
 
-// Tail returns the last n elements of the Series.
+// Tail returns the last n elements of the Series, wrapped in a primitive.Optional.
+// The second element is true for every value that is null.
+// The third element is an error if the user's request is invalid or misformatted.
 func (s *Series) Tail(n int) ([]interface{}, []bool, error) {
-	if n > s.ca.Len() {
-		return nil, nil, fmt.Errorf("n %d is greater than the length of the Series %d", n, s.ca.Len())
+	if n < 0 || n > s.ca.Len() {
+		return nil, nil, fmt.Errorf("%w: n %d must be between 0 and the length of the Series %d", mangoerr.ErrOutOfBounds, n, s.ca.Len())
 	}
 	ret := make([]interface{}, n)
 	nulls := make([]bool, n)
+	offset := s.ca.Len() - n
 	for i := 0; i < n; i++ {
 		// Don't check errors, because we already checked the length
-		val, _ := s.Value(i)
+		val, _ := s.Value(offset + i)
 		ret[i] = val
+		nulls[i] = !val.Valid
 	}
 	return ret, nulls, nil
 }
 
 // IsValid returns true if the value at index i is not null.
 func (s *Series) IsValid(i int) (bool, error) {
-	if i > s.Len() {
-		return false, fmt.Errorf("index out of range")
+	if i < 0 || i >= s.Len() {
+		return false, &mangoerr.OutOfBoundsError{Index: i, Length: s.Len()}
 	}
 	chunkIndex, i := s.ResolveIndex(i)
 	return s.Chunks()[chunkIndex].IsValid(i), nil
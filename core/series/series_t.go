@@ -3,6 +3,8 @@ package series
 import (
 	"fmt"
 
+	"github.com/kstremick/mango/core/chunked"
+	"github.com/kstremick/mango/core/mangoerr"
 	"github.com/kstremick/mango/core/primitive"
 
 	"github.com/apache/arrow/go/v12/arrow"
@@ -12,38 +14,113 @@ type SeriesT[T primitive.Primitive] struct {
 	Series
 }
 
+// NewSeriesTFromArray creates a new typed Series from an arrow Array.
+// The type of the array is not checked, so it must be T:
+// use NewSeriesTFromArrayChecked, or AsT on a Series, to check it.
 func NewSeriesTFromArray[T primitive.Primitive](name string, arr arrow.Array) SeriesT[T] {
-	return SeriesT[T]{
-		Series: NewSeriesFromArray(name, arr),
+	return SeriesT[T]{Series: NewSeriesFromArray(name, arr)}
+}
+
+// NewSeriesTFromArrayChecked is like NewSeriesTFromArray, but returns an error if the type of the array is not T.
+func NewSeriesTFromArrayChecked[T primitive.Primitive](name string, arr arrow.Array) (SeriesT[T], error) {
+	s := NewSeriesFromArray(name, arr)
+	ret, err := AsT[T](s)
+	if err != nil {
+		s.Release()
+		return SeriesT[T]{}, err
 	}
+	return ret, nil
 }
 
-// NewSeriesTFromTSlice creates a new Series from a slice of interface{}.
+// NewSeriesTFromTSlice creates a new Series from a slice of T.
 // The valid slice determines which values in v are valid (not null).
 // The valid slice must either be empty or be equal in length to v.
 // If empty, all values in v are appended and considered valid.
+// It panics on errors, see NewSeriesTFromTSliceChecked.
 func NewSeriesTFromTSlice[T primitive.Primitive](name string, vals []T, valid []bool) SeriesT[T] {
-	series := NewSeriesFromSlice(name, any(vals).([]interface{}), valid, false)
-	seriesT := SeriesT[T]{Series: series}
-	err := seriesT.Validate()
+	seriesT, err := NewSeriesTFromTSliceChecked(name, vals, valid)
 	if err != nil {
-		// We passed a []T so this should be impossible
 		panic(err)
 	}
 	return seriesT
 }
 
+// NewSeriesTFromTSliceChecked is like NewSeriesTFromTSlice,
+// but returns an error if the valid slice has the wrong length.
+func NewSeriesTFromTSliceChecked[T primitive.Primitive](name string, vals []T, valid []bool) (SeriesT[T], error) {
+	if len(valid) > 0 && len(valid) != len(vals) {
+		return SeriesT[T]{}, &mangoerr.ShapeMismatchError{What: "length of valid", Expected: len(vals), Actual: len(valid)}
+	}
+	seriesT := SeriesT[T]{Series: newSeriesT(name, vals, valid)}
+	// We passed a []T so this cannot fail.
+	return seriesT, seriesT.Validate()
+}
+
 func NewSeriesTFromT[T primitive.Primitive](name string, val T) SeriesT[T] {
 	return NewSeriesTFromTSlice(name, []T{val}, nil)
 }
 
+// NewSeriesTFromOptionals creates a new typed Series from a slice of optional values,
+// where values that are not valid are nulls.
+func NewSeriesTFromOptionals[T primitive.Primitive](name string, vals []primitive.Optional[T]) SeriesT[T] {
+	values := make([]T, len(vals))
+	valid := make([]bool, len(vals))
+	for i, v := range vals {
+		values[i], valid[i] = v.Value, v.Valid
+	}
+	return NewSeriesTFromTSlice(name, values, valid)
+}
+
+// AsT returns the Series as a typed Series of type T, sharing its data.
+// Returns an error if the Series is not of type T.
+func AsT[T primitive.Primitive](s Series) (SeriesT[T], error) {
+	ret := SeriesT[T]{Series: s}
+	if err := ret.Validate(); err != nil {
+		return SeriesT[T]{}, fmt.Errorf("series %s: %w", s.Name, err)
+	}
+	return ret, nil
+}
+
+// AsTExn is like AsT, but panics on errors.
+func AsTExn[T primitive.Primitive](s Series) SeriesT[T] {
+	ret, err := AsT[T](s)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// AsInt64 returns the Series as a typed Series of type int64.
+// Returns an error if this is not possible.
+func (s *Series) AsInt64() (SeriesT[int64], error) {
+	return AsT[int64](*s)
+}
+
+// AsFloat64 returns the Series as a typed Series of type float64.
+// Returns an error if this is not possible.
+func (s *Series) AsFloat64() (SeriesT[float64], error) {
+	return AsT[float64](*s)
+}
+
+// AsBool returns the Series as a typed Series of type bool.
+// Returns an error if this is not possible.
+func (s *Series) AsBool() (SeriesT[bool], error) {
+	return AsT[bool](*s)
+}
+
+// AsString returns the Series as a typed Series of type string.
+// Returns an error if this is not possible.
+func (s *Series) AsString() (SeriesT[string], error) {
+	return AsT[string](*s)
+}
+
 // Validate checks that the type T corresponds to the underying data.
 // Returns an error if this is not true.
 // Make sure to call this when you instantiate a new SeriesT.
 func (s *SeriesT[T]) Validate() error {
 	datatype := primitive.ToArrowDatatypeT[T]()
 	if s.ca.DataType().ID() != datatype.ID() {
-		return fmt.Errorf("the underlying datatype is not %s", datatype.Name())
+		return &mangoerr.TypeMismatchError{Expected: datatype.Name(), Actual: s.ca.DataType()}
 	}
 	return nil
 }
@@ -51,11 +128,28 @@ func (s *SeriesT[T]) Validate() error {
 // Value returns the value at index i as type T, wrapped in a primitive.Optional.
 // Returns an error if this is not possible.
 func (s *SeriesT[T]) Value(i int) (primitive.Optional[T], error) {
-	v, err := s.Series.Value(i)
+	if i < 0 || i >= s.Len() {
+		return primitive.None[T](), &mangoerr.OutOfBoundsError{Index: i, Length: s.Len()}
+	}
+	chunkIndex, i := s.ResolveIndex(i)
+	chunk := s.Chunks()[chunkIndex]
+	if chunk.IsNull(i) {
+		return primitive.None[T](), nil
+	}
+	extractValueFn, err := chunked.ExtractValueFnT[T](chunk)
 	if err != nil {
 		return primitive.None[T](), err
 	}
-	return any(v).(primitive.Optional[T]), nil
+	return primitive.Some(extractValueFn(i)), nil
+}
+
+// ValueExn is like Value, but panics on errors.
+func (s *SeriesT[T]) ValueExn(i int) primitive.Optional[T] {
+	v, err := s.Value(i)
+	if err != nil {
+		panic(err)
+	}
+	return v
 }
 
 // Len returns the length of the Series.
@@ -63,46 +157,59 @@ func (s *SeriesT[T]) Len() int {
 	return s.Series.Len()
 }
 
-// Filter returns a new SeriesT[T] with only the values where the mask is true.
-// func (s *SeriesT[T]) Filter(mask *SeriesT[bool]) (SeriesT[T], error) {
-// 	rets := make([]T, mask.Len())
-// 	retsI := 0
-// 	for i := 0; i < mask.Len(); i++ {
-// 		v, err := mask.Value(i)
-// 		if err != nil {
-// 			return SeriesT[T]{}, err
-// 		}
-// 		// if v is Nill, value should be the default value, which is false.
-// 		if v.Value {
-// 			v, e := s.Value(i)
-// 			if e != nil {
-// 				return SeriesT[T]{}, err
-// 			}
-// 			rets[retsI] = v.Value
-// 			retsI++
-// 		}
-// 	}
-// 	return NewSeriesTFromTSlice[T](s.Name, rets, nil), nil
-// }
+// ToSlice returns the values of the Series, and whether each of them is valid (not null).
+// Null values are the zero value of T.
+// Returns an error if the data of the Series is not of type T.
+func (s *SeriesT[T]) ToSlice() ([]T, []bool, error) {
+	if err := s.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return valuesT[T](&s.Series)
+}
 
-// AsFloat64 returns the Series as a typed Series of type float64.
-// Returns an error if this is not possible.
-// func (s *Series) AsFloat64() (SeriesT[float64], error) {
-// 	ret := SeriesT[float64]{Name: s.Name, ca: s.ca}
-// 	err := ret.Validate()
-// 	if err != nil {
-// 		return SeriesT[float64]{}, err
-// 	}
-// 	return ret, nil
-// }
+// ToSliceExn is like ToSlice, but panics on errors.
+func (s *SeriesT[T]) ToSliceExn() ([]T, []bool) {
+	vals, valids, err := s.ToSlice()
+	if err != nil {
+		panic(err)
+	}
+	return vals, valids
+}
 
-// AsString returns the Series as a typed Series of type string.
-// Returns an error if this is not possible.
-// func (s *Series) AsString() (SeriesT[string], error) {
-// 	ret := SeriesT[string]{Name: s.Name, ca: s.ca}
-// 	err := ret.Validate()
-// 	if err != nil {
-// 		return SeriesT[string]{}, err
-// 	}
-// 	return ret, nil
-// }
+// Filter keeps the values where the mask is true, see Series.Filter.
+func (s *SeriesT[T]) Filter(mask *SeriesT[bool]) (SeriesT[T], error) {
+	return typed[T](s.Series.Filter(mask))
+}
+
+// Take keeps the values at the given indices, see Series.Take.
+func (s *SeriesT[T]) Take(indices *SeriesT[int64]) (SeriesT[T], error) {
+	return typed[T](s.Series.Take(indices))
+}
+
+// Slice returns a zero-copy slice of the Series, see Series.Slice.
+func (s *SeriesT[T]) Slice(offset, length int64) (SeriesT[T], error) {
+	return typed[T](s.Series.Slice(offset, length))
+}
+
+// Map applies fn to every valid value of the Series, keeping nulls.
+// Returns an error if the data of the Series is not of type T.
+func (s *SeriesT[T]) Map(fn func(T) T) (SeriesT[T], error) {
+	vals, valids, err := s.ToSlice()
+	if err != nil {
+		return SeriesT[T]{}, err
+	}
+	for i, v := range vals {
+		if valids[i] {
+			vals[i] = fn(v)
+		}
+	}
+	return NewSeriesTFromTSliceChecked(s.Name, vals, valids)
+}
+
+// typed wraps the result of an operation on the Series of a SeriesT[T], which keeps its type.
+func typed[T primitive.Primitive](s Series, err error) (SeriesT[T], error) {
+	if err != nil {
+		return SeriesT[T]{}, err
+	}
+	return SeriesT[T]{Series: s}, nil
+}
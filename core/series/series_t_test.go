@@ -0,0 +1,91 @@
+package series_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/zeebo/assert"
+)
+
+func TestSeriesTConstructors(t *testing.T) {
+	fromOptionals := series.NewSeriesTFromOptionals("a", []primitive.Optional[float64]{
+		primitive.Some(1.5), primitive.None[float64](), primitive.Some(2.5),
+	})
+	vals, valids := fromOptionals.ToSliceExn()
+	assert.Equal(t, []float64{1.5, 0, 2.5}, vals)
+	assert.Equal(t, []bool{true, false, true}, valids)
+	assert.Equal(t, primitive.None[float64](), fromOptionals.ValueExn(1))
+
+	ids := series.NewSeriesTFromTSlice("id", []seriesID{4, 5}, nil)
+	assert.Equal(t, primitive.Some(seriesID(5)), ids.ValueExn(1))
+	idVals, _ := ids.ToSliceExn()
+	assert.Equal(t, []seriesID{4, 5}, idVals)
+}
+
+func TestAsT(t *testing.T) {
+	s := series.NewSeries("a", []string{"x", "y"})
+	strs, err := series.AsT[string](s)
+	assert.NoError(t, err)
+	assert.Equal(t, primitive.Some("y"), strs.ValueExn(1))
+
+	same, err := s.AsString()
+	assert.NoError(t, err)
+	assert.Equal(t, strs.Len(), same.Len())
+
+	_, err = series.AsT[int64](s)
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+	_, err = s.AsFloat64()
+	assert.Error(t, err)
+	_, err = s.AsBool()
+	assert.Error(t, err)
+	_, err = s.AsInt64()
+	assert.Error(t, err)
+
+	arr := s.Chunks()[0]
+	_, err = series.NewSeriesTFromArrayChecked[int64]("a", arr)
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+	// NewSeriesTFromArray does not check the type, which ToSlice reports instead.
+	unchecked := series.NewSeriesTFromArray[int64]("a", arr)
+	_, _, err = unchecked.ToSlice()
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+	_, err = unchecked.Map(func(v int64) int64 { return v })
+	assert.Error(t, err)
+}
+
+func TestSeriesTOperations(t *testing.T) {
+	s := series.NewSeriesTFromTSlice("a", []int64{1, 2, 3, 4}, []bool{true, true, false, true})
+	type testCase struct {
+		name   string
+		fn     func() (series.SeriesT[int64], error)
+		vals   []int64
+		valids []bool
+	}
+	mask := series.NewSeriesTFromTSlice("m", []bool{true, false, true, true}, nil)
+	indices := series.NewSeriesTFromTSlice("i", []int64{3, 0}, nil)
+	testCases := []testCase{
+		{"filter", func() (series.SeriesT[int64], error) { return s.Filter(&mask) }, []int64{1, 0, 4}, []bool{true, false, true}},
+		{"take", func() (series.SeriesT[int64], error) { return s.Take(&indices) }, []int64{4, 1}, []bool{true, true}},
+		{"slice", func() (series.SeriesT[int64], error) { return s.Slice(1, 2) }, []int64{2, 0}, []bool{true, false}},
+		{"map", func() (series.SeriesT[int64], error) {
+			return s.Map(func(v int64) int64 { return v * 10 })
+		}, []int64{10, 20, 0, 40}, []bool{true, true, false, true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.fn()
+			assert.NoError(t, err)
+			vals, valids := got.ToSliceExn()
+			assert.Equal(t, tc.vals, vals)
+			assert.Equal(t, tc.valids, valids)
+		})
+	}
+
+	_, err := s.Slice(3, 2)
+	assert.True(t, errors.Is(err, mangoerr.ErrOutOfBounds))
+	_, err = s.Value(4)
+	assert.True(t, errors.Is(err, mangoerr.ErrOutOfBounds))
+}
@@ -171,3 +171,116 @@ func TestAllAndAny(t *testing.T) {
 
 	assert.Equal(t, res, false)
 }
+
+func TestCast(t *testing.T) {
+	ser := series.NewSeries("test", []interface{}{int64(1), primitive.Null{}, int64(3)})
+
+	floats, err := ser.Cast(arrow.PrimitiveTypes.Float64)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", floats.Name)
+	assert.Equal(t, arrow.FLOAT64, floats.Type())
+	assert.Equal(t, 3.0, floats.ValueExn(2).Value)
+	assert.Equal(t, 1, floats.NullN())
+
+	strs, err := ser.Cast(arrow.BinaryTypes.String)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", strs.ValueExn(0).Value)
+
+	floats = series.NewSeries("test", []float64{1.5})
+	_, err = floats.Cast(arrow.PrimitiveTypes.Int64)
+	assert.Error(t, err)
+}
+
+func TestAppendAndExtend(t *testing.T) {
+	ser := series.NewSeries("test", []int64{1, 2})
+	other := series.NewSeries("other", []int64{3})
+
+	appended, err := ser.Append(other)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", appended.Name)
+	assert.Equal(t, 3, appended.Len())
+	assert.Equal(t, 2, appended.NumChunks())
+	assert.Equal(t, int64(3), appended.ValueExn(2).Value)
+	assert.Equal(t, 2, ser.Len())
+
+	err = ser.Extend(other)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, ser.Len())
+	assert.Equal(t, 1, ser.NumChunks())
+	assert.Equal(t, int64(3), ser.ValueExn(2).Value)
+
+	strs := series.NewSeries("test", []string{"a"})
+	_, err = ser.Append(strs)
+	assert.Error(t, err)
+	assert.Error(t, ser.Extend(strs))
+}
+
+func TestRechunk(t *testing.T) {
+	type testCase struct {
+		name  string
+		first series.Series
+		other series.Series
+	}
+	testCases := []testCase{
+		{"int64", series.NewSeriesFromSlice("a", []int64{1, 0}, []bool{true, false}, false), series.NewSeries("a", []int64{3})},
+		{"float64", series.NewSeries("a", []float64{1.5}), series.NewSeriesFromSlice("a", []float64{0, 2.5}, []bool{false, true}, false)},
+		{"bool", series.NewSeries("a", []bool{true}), series.NewSeries("a", []bool{false, true})},
+		{"string", series.NewSeries("a", []string{"x", "y"}), series.NewSeriesFromSlice("a", []string{""}, []bool{false}, false)},
+		{"timestamp", timestampSeries(t, 1, 2), timestampSeries(t, 3)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appended, err := tc.first.Append(tc.other)
+			assert.NoError(t, err)
+			want := make([]primitive.Optional[interface{}], appended.Len())
+			for i := range want {
+				want[i] = appended.ValueExn(i)
+			}
+
+			assert.NoError(t, appended.Rechunk())
+			assert.Equal(t, 1, appended.NumChunks())
+			assert.Equal(t, tc.first.DataType(), appended.DataType())
+			for i := range want {
+				assert.Equal(t, want[i], appended.ValueExn(i))
+			}
+		})
+	}
+}
+
+func timestampSeries(t *testing.T, vals ...int64) series.Series {
+	b := array.NewTimestampBuilder(memory.NewGoAllocator(), &arrow.TimestampType{Unit: arrow.Second})
+	defer b.Release()
+	for _, v := range vals {
+		b.Append(arrow.Timestamp(v))
+	}
+	return series.NewSeriesFromArray("a", b.NewArray())
+}
+
+func TestHeadAndTail(t *testing.T) {
+	ser := series.NewSeries("test", []interface{}{int64(1), int64(2), primitive.Null{}})
+
+	head, err := ser.Head(2)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{primitive.Some[interface{}](int64(1)), primitive.Some[interface{}](int64(2))}, head)
+
+	tail, nulls, err := ser.Tail(2)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{primitive.Some[interface{}](int64(2)), primitive.None[interface{}]()}, tail)
+	assert.DeepEqual(t, []bool{false, true}, nulls)
+
+	_, _, err = ser.Tail(4)
+	assert.Error(t, err)
+}
+
+func TestFilter(t *testing.T) {
+	ser := series.NewSeries("test", []interface{}{int64(1), primitive.Null{}, int64(3)})
+	mask := series.NewSeriesTFromTSlice("", []bool{true, true, false}, nil)
+	filtered, err := ser.Filter(&mask)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, filtered.Len())
+	assert.Equal(t, 1, filtered.NullN())
+
+	every := ser.TakeEveryExn(2)
+	assert.Equal(t, 2, every.Len())
+	assert.Equal(t, int64(3), every.ValueExn(1).Value)
+}
@@ -4,6 +4,10 @@ go 1.20
 
 require (
 	github.com/apache/arrow/go/v12 v12.0.0
+	github.com/google/flatbuffers v2.0.8+incompatible
+	github.com/klauspost/compress v1.15.9
+	github.com/mattn/go-sqlite3 v1.14.17
+	github.com/pierrec/lz4/v4 v4.1.15
 	github.com/zeebo/assert v1.3.1
 	golang.org/x/exp v0.0.0-20230510235704-dd950f8aeaea
 )
@@ -15,13 +19,10 @@ require (
 	github.com/goccy/go-json v0.9.11 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/flatbuffers v2.0.8+incompatible // indirect
 	github.com/klauspost/asmfmt v1.3.2 // indirect
-	github.com/klauspost/compress v1.15.9 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
 	github.com/minio/asm2plan9s v0.0.0-20200509001527-cdd76441f9d8 // indirect
 	github.com/minio/c2goasm v0.0.0-20190812172519-36a3d3bbc4f3 // indirect
-	github.com/pierrec/lz4/v4 v4.1.15 // indirect
 	github.com/zeebo/xxh3 v1.0.2 // indirect
 	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/net v0.7.0 // indirect
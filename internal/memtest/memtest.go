@@ -0,0 +1,42 @@
+// Package memtest finds memory leaks of mango operations in tests.
+package memtest
+
+import (
+	"testing"
+
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+)
+
+// CheckedAllocator makes mango allocate with a memory.CheckedAllocator for the duration of the test,
+// and fails the test if any memory is still allocated at its end.
+func CheckedAllocator(t testing.TB) *memory.CheckedAllocator {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	series.SetDefaultAllocator(mem)
+	t.Cleanup(func() {
+		series.SetDefaultAllocator(nil)
+		mem.AssertSize(t, 0)
+	})
+	return mem
+}
+
+// Case is an operation returning a result that holds its own reference to its memory.
+type Case[T any] struct {
+	Name string
+	Fn   func() (T, error)
+}
+
+// Run runs every case as a subtest, and releases its result with release.
+// Used with CheckedAllocator, the test fails if a case leaks memory.
+func Run[T any](t *testing.T, cases []Case[T], release func(T)) {
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			out, err := tc.Fn()
+			if err != nil {
+				t.Fatal(err)
+			}
+			release(out)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package partition groups the rows of columns by their values.
+package partition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kstremick/mango/core/series"
+)
+
+// Rows groups the row indices [0, height) by the values of the keys, which must have height values.
+// Partitions are ordered by their first row. Without keys, all rows are in a single partition.
+func Rows(keys []series.Series, height int) [][]int64 {
+	var partitions [][]int64
+	partitionIndex := make(map[string]int)
+	var sb strings.Builder
+	for row := 0; row < height; row++ {
+		sb.Reset()
+		for _, col := range keys {
+			v := col.ValueExn(row)
+			if !v.Valid {
+				sb.WriteString("-")
+				continue
+			}
+			// Values are length prefixed, so that no value can run into the next one.
+			value := fmt.Sprint(v.Value)
+			fmt.Fprintf(&sb, "%d:%s", len(value), value)
+		}
+		key := sb.String()
+		i, ok := partitionIndex[key]
+		if !ok {
+			i = len(partitions)
+			partitionIndex[key] = i
+			partitions = append(partitions, nil)
+		}
+		partitions[i] = append(partitions[i], int64(row))
+	}
+	return partitions
+}
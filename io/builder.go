@@ -0,0 +1,78 @@
+package io
+
+import (
+	"fmt"
+
+	"github.com/kstremick/mango/core/primitive"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// appendValue appends a value decoded by a reader to b, converting it to the type of the builder.
+// Values are Go primitives, and nested values are *jsonObject for structs and []interface{} for lists.
+// A nil value appends a null. Strings are parsed into numbers and booleans, and integers are converted to floats,
+// but booleans and numbers are not converted into each other, nor into strings.
+func appendValue(b array.Builder, v interface{}) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	_, isBool := v.(bool)
+	var ok bool
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		if !isBool {
+			var val int64
+			if val, ok = primitive.AttemptConversionT[int64](v); ok {
+				b.Append(val)
+			}
+		}
+	case *array.Float64Builder:
+		if !isBool {
+			var val float64
+			if val, ok = primitive.AttemptConversionT[float64](v); ok {
+				b.Append(val)
+			}
+		}
+	case *array.BooleanBuilder:
+		switch v.(type) {
+		case bool, string:
+			var val bool
+			if val, ok = primitive.AttemptConversionT[bool](v); ok {
+				b.Append(val)
+			}
+		}
+	case *array.StringBuilder:
+		var val string
+		if val, ok = v.(string); ok {
+			b.Append(val)
+		}
+	case *array.StructBuilder:
+		var obj *jsonObject
+		if obj, ok = v.(*jsonObject); ok {
+			b.Append(true)
+			for f, field := range b.Type().(*arrow.StructType).Fields() {
+				if err := appendValue(b.FieldBuilder(f), obj.values[field.Name]); err != nil {
+					return fmt.Errorf("%s: %w", field.Name, err)
+				}
+			}
+		}
+	case *array.ListBuilder:
+		var arr []interface{}
+		if arr, ok = v.([]interface{}); ok {
+			b.Append(true)
+			for _, elem := range arr {
+				if err := appendValue(b.ValueBuilder(), elem); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", b.Type())
+	}
+	if !ok {
+		return fmt.Errorf("cannot convert %v to %s", v, b.Type())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Compression is the compression format of a file.
+type Compression int
+
+const (
+	// NoCompression leaves the data uncompressed.
+	NoCompression Compression = iota
+	// Gzip is the gzip format, with the .gz extension.
+	Gzip
+	// Zstd is the Zstandard format, with the .zst extension.
+	Zstd
+	// Bzip2 is the bzip2 format, with the .bz2 extension. It can only be read.
+	Bzip2
+	// LZ4 is the LZ4 frame format, with the .lz4 extension.
+	LZ4
+)
+
+// compressionMagic holds the bytes that start the data of every compression format.
+var compressionMagic = map[Compression][]byte{
+	Gzip:  {0x1f, 0x8b},
+	Zstd:  {0x28, 0xb5, 0x2f, 0xfd},
+	Bzip2: []byte("BZh"),
+	LZ4:   {0x04, 0x22, 0x4d, 0x18},
+}
+
+// CompressionFromPath returns the compression format matching the extension of path.
+func CompressionFromPath(path string) Compression {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz", ".gzip":
+		return Gzip
+	case ".zst", ".zstd":
+		return Zstd
+	case ".bz2":
+		return Bzip2
+	case ".lz4":
+		return LZ4
+	}
+	return NoCompression
+}
+
+// Decompress returns a reader of the decompressed data of input,
+// detecting its compression format from its first bytes.
+// Uncompressed data is returned as is.
+// Closing the returned reader does not close input.
+func Decompress(input io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(input)
+	return newDecompressor(buffered, detectCompression(buffered))
+}
+
+// detectCompression peeks at the first bytes of r to find its compression format.
+func detectCompression(r *bufio.Reader) Compression {
+	for c, magic := range compressionMagic {
+		if head, err := r.Peek(len(magic)); err == nil && bytes.Equal(head, magic) {
+			return c
+		}
+	}
+	return NoCompression
+}
+
+func newDecompressor(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case NoCompression:
+		return io.NopCloser(r), nil
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case Bzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	case LZ4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	}
+	return nil, fmt.Errorf("unknown compression %d", c)
+}
+
+// NewCompressedWriter returns a writer compressing the data written to it into output.
+// The returned writer must be closed to flush the compressed data, which does not close output.
+func NewCompressedWriter(output io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case NoCompression:
+		return nopWriteCloser{output}, nil
+	case Gzip:
+		return gzip.NewWriter(output), nil
+	case Zstd:
+		return zstd.NewWriter(output)
+	case Bzip2:
+		return nil, errors.New("writing bzip2 is not supported")
+	case LZ4:
+		return lz4.NewWriter(output), nil
+	}
+	return nil, fmt.Errorf("unknown compression %d", c)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package io_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kstremick/mango/io"
+
+	"github.com/zeebo/assert"
+)
+
+func TestCompressedCsv(t *testing.T) {
+	df, err := io.ReadCsvFile("testdata/titanic.csv")
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	for _, name := range []string{"titanic.csv", "titanic.csv.gz", "titanic.csv.zst", "titanic.csv.lz4"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, io.WriteCsvFile(df, path))
+			out, err := io.ReadCsvFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, df.String(), out.String())
+		})
+	}
+
+	assert.Error(t, io.WriteCsvFile(df, filepath.Join(dir, "titanic.csv.bz2")))
+
+	small, err := io.ReadCsvFile("testdata/small.csv.bz2")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "name"}, small.GetColumnNames())
+	assert.Equal(t, 2, small.Height())
+}
+
+func TestCompressionDetection(t *testing.T) {
+	type testCase struct {
+		compression io.Compression
+		extension   string
+	}
+	testCases := []testCase{
+		{io.NoCompression, ".json"},
+		{io.Gzip, ".gz"},
+		{io.Zstd, ".zst"},
+		{io.LZ4, ".lz4"},
+	}
+	data := []byte(`[{"id": 1}, {"id": 2}]`)
+	for _, tc := range testCases {
+		t.Run(tc.extension, func(t *testing.T) {
+			assert.Equal(t, tc.compression, io.CompressionFromPath("data"+tc.extension))
+
+			var buf bytes.Buffer
+			w, err := io.NewCompressedWriter(&buf, tc.compression)
+			assert.NoError(t, err)
+			_, err = w.Write(data)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			r, err := io.Decompress(&buf)
+			assert.NoError(t, err)
+			df, err := io.ReadJSON(r, io.JSONOptions{})
+			assert.NoError(t, err)
+			assert.NoError(t, r.Close())
+			assert.Equal(t, 2, df.Height())
+
+			// Files without a compression extension are detected by their first bytes.
+			path := filepath.Join(t.TempDir(), "data.json")
+			w, err = io.NewCompressedWriter(&buf, tc.compression)
+			assert.NoError(t, err)
+			_, err = w.Write(data)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+			assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+			df, err = io.ReadJSONFile(path, io.JSONOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, df.Height())
+		})
+	}
+}
+
+func TestCompressedFiles(t *testing.T) {
+	df := ipcTestDataFrame(t)
+	dir := t.TempDir()
+
+	assert.NoError(t, io.WriteNDJSONFile(df, filepath.Join(dir, "data.ndjson.gz")))
+	f, err := os.Open(filepath.Join(dir, "data.ndjson.gz"))
+	assert.NoError(t, err)
+	defer f.Close()
+	_, err = gzip.NewReader(f)
+	assert.NoError(t, err)
+	out, err := io.ReadNDJSONFile(filepath.Join(dir, "data.ndjson.gz"), io.JSONOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, df.Height(), out.Height())
+
+	assert.NoError(t, io.WriteIPCFile(df, filepath.Join(dir, "data.arrow.zst"), io.IPCOptions{}))
+	out, err = io.ReadIPCFile(filepath.Join(dir, "data.arrow.zst"))
+	assert.NoError(t, err)
+	assert.Equal(t, df.String(), out.String())
+}
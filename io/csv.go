@@ -2,12 +2,15 @@ package io
 
 import (
 	rawcsv "encoding/csv"
+	"fmt"
 	"io"
-	"os"
+	"io/fs"
 	"strings"
 
 	"github.com/kstremick/mango/core/dataframe"
 	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
 )
 
 // ReadCsv reads a CSV file from an io.Reader and returns a DataFrame
@@ -18,6 +21,9 @@ func ReadCsv(input io.Reader) (*dataframe.DataFrame, error) {
 	if err != nil {
 		return &df, err
 	}
+	if len(lines) == 0 {
+		return &df, fmt.Errorf("missing CSV header")
+	}
 	names := lines[0]
 	var data [][]interface{} = make([][]interface{}, len(names))
 	for _, line := range lines[1:] {
@@ -27,14 +33,33 @@ func ReadCsv(input io.Reader) (*dataframe.DataFrame, error) {
 	}
 	seriesSlice := make([]series.Series, len(names))
 	for i, name := range names {
-		seriesSlice[i] = series.NewSeriesFromSlice(name, data[i], nil, true)
+		if len(data[i]) == 0 {
+			// Without rows there is nothing to infer the type from.
+			seriesSlice[i] = series.NewEmptySeries(name, arrow.BinaryTypes.String)
+			continue
+		}
+		seriesSlice[i], err = series.NewSeriesFromSliceChecked(name, data[i], nil, true)
+		if err != nil {
+			for _, s := range seriesSlice[:i] {
+				s.Release()
+			}
+			return &df, err
+		}
 	}
 	return dataframe.NewDataFrame(seriesSlice), nil
 }
 
 // ReadCsvFile reads a CSV file from a path and returns a DataFrame
+// Compressed files are decompressed, see CompressionFromPath.
 func ReadCsvFile(path string) (*dataframe.DataFrame, error) {
-	file, err := os.Open(path)
+	fsys, name := localFile(path)
+	return ReadCsvFS(fsys, name)
+}
+
+// ReadCsvFS reads the named CSV file of a file system and returns a DataFrame
+// Compressed files are decompressed, see CompressionFromPath.
+func ReadCsvFS(fsys fs.FS, name string) (*dataframe.DataFrame, error) {
+	file, err := openFile(fsys, name)
 	if err != nil {
 		return &dataframe.DataFrame{}, err
 	}
@@ -46,3 +71,44 @@ func ReadCsvFile(path string) (*dataframe.DataFrame, error) {
 func ReadCsvString(s string) (*dataframe.DataFrame, error) {
 	return ReadCsv(strings.NewReader(s))
 }
+
+// WriteCsv writes a DataFrame to an io.Writer as CSV, with a header row of column names.
+// Null values are written as empty fields.
+func WriteCsv(output io.Writer, df *dataframe.DataFrame) error {
+	if err := df.Validate(); err != nil {
+		return err
+	}
+	w := rawcsv.NewWriter(output)
+	if err := w.Write(df.GetColumnNames()); err != nil {
+		return err
+	}
+	record := make([]string, len(df.Series))
+	for i := 0; i < df.Height(); i++ {
+		for j, col := range df.Series {
+			record[j] = ""
+			if v := col.ValueExn(i); v.Valid {
+				record[j] = fmt.Sprint(v.Value)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// WriteCsvFile writes a DataFrame to a CSV file
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteCsvFile(df *dataframe.DataFrame, path string) error {
+	fsys, name := localFile(path)
+	return WriteCsvFS(fsys, df, name)
+}
+
+// WriteCsvFS writes a DataFrame to the named CSV file of a file system
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteCsvFS(fsys WriteFS, df *dataframe.DataFrame, name string) error {
+	return writeFile(fsys, name, func(w io.Writer) error {
+		return WriteCsv(w, df)
+	})
+}
@@ -0,0 +1,104 @@
+package io
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFS is a file system that files can also be written to.
+// Readers accept any fs.FS, and writers accept a WriteFS.
+type WriteFS interface {
+	fs.FS
+	// Create creates or truncates the named file, and returns a writer to it.
+	// The file is complete once the writer is closed.
+	Create(name string) (io.WriteCloser, error)
+	// MkdirAll creates the named directory, along with any missing parents.
+	MkdirAll(name string) error
+}
+
+// LocalFS returns a WriteFS for the tree of files rooted at the directory root of the local disk.
+func LocalFS(root string) WriteFS {
+	return localFS{FS: os.DirFS(root), root: root}
+}
+
+type localFS struct {
+	fs.FS
+	root string
+}
+
+func (l localFS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(l.root, filepath.FromSlash(name)), nil
+}
+
+func (l localFS) Create(name string) (io.WriteCloser, error) {
+	path, err := l.path("create", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+func (l localFS) MkdirAll(name string) error {
+	path, err := l.path("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0o755)
+}
+
+// localFile returns a LocalFS of the directory of a path of the operating system, and the name of the file in it.
+// It backs the path-based readers and writers.
+func localFile(path string) (WriteFS, string) {
+	return LocalFS(filepath.Dir(path)), filepath.Base(path)
+}
+
+// openFile opens the named file of fsys for reading, decompressing it.
+// The compression format is found from the extension of name, or else from the first bytes of the file.
+func openFile(fsys fs.FS, name string) (io.ReadCloser, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	buffered := bufio.NewReader(file)
+	c := CompressionFromPath(name)
+	if c == NoCompression {
+		c = detectCompression(buffered)
+	}
+	r, err := newDecompressor(buffered, c)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &decompressedFile{ReadCloser: r, file: file}, nil
+}
+
+// decompressedFile closes both the decompressor and the file it reads from.
+type decompressedFile struct {
+	io.ReadCloser
+	file fs.File
+}
+
+func (f *decompressedFile) Close() error {
+	return errors.Join(f.ReadCloser.Close(), f.file.Close())
+}
+
+// writeFile creates the named file of fsys and writes to it with write,
+// compressing the data according to the extension of name.
+func writeFile(fsys WriteFS, name string, write func(w io.Writer) error) error {
+	file, err := fsys.Create(name)
+	if err != nil {
+		return err
+	}
+	w, err := NewCompressedWriter(file, CompressionFromPath(name))
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+	return errors.Join(write(w), w.Close(), file.Close())
+}
@@ -0,0 +1,407 @@
+package io
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/partition"
+
+	"github.com/apache/arrow/go/v12/arrow"
+)
+
+// hiveNullPartition is the partition value Hive uses for null keys.
+const hiveNullPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// ReadFileFunc reads a single file into a DataFrame.
+type ReadFileFunc func(path string) (*dataframe.DataFrame, error)
+
+// ReadFSFunc reads the named file of a file system into a DataFrame.
+type ReadFSFunc func(fsys fs.FS, name string) (*dataframe.DataFrame, error)
+
+// ReadGlob reads every file matching the glob pattern with read, and concatenates them in path order.
+// Files are read concurrently, and must all have the same columns.
+// Directories named like key=value, as in Hive-partitioned datasets, become columns named key
+// appended after the columns of the files. Their type is inferred from the values of every file.
+func ReadGlob(pattern string, read ReadFileFunc) (*dataframe.DataFrame, error) {
+	ds, err := OpenDataset(pattern, read)
+	if err != nil {
+		return nil, err
+	}
+	return ds.Scan(nil)
+}
+
+// ReadGlobFS reads every file of a file system matching the glob pattern with read, see ReadGlob.
+func ReadGlobFS(fsys fs.FS, pattern string, read ReadFSFunc) (*dataframe.DataFrame, error) {
+	ds, err := OpenDatasetFS(fsys, pattern, read)
+	if err != nil {
+		return nil, err
+	}
+	return ds.Scan(nil)
+}
+
+// Dataset is the set of files matching a glob pattern, possibly Hive-partitioned.
+// Opening a dataset only lists its files and parses their partitions,
+// so Scan can skip the files of partitions that are not needed without reading them.
+type Dataset struct {
+	fsys  fs.FS
+	read  ReadFSFunc
+	paths []string
+	keys  []string
+	// partitions holds the values of the partition keys for every path.
+	partitions [][]primitive.Optional[string]
+}
+
+// unifyFrames casts the columns of the frames read from the files of a dataset to common types, in place,
+// so that they can be concatenated. The types of frames without rows are ignored, since they cannot be inferred
+// from values, like the string columns of header-only CSV files, and int64 columns are widened to float64
+// when other files hold floats in the same column.
+func unifyFrames(frames []*dataframe.DataFrame) error {
+	dtypes := make(map[string]arrow.DataType)
+	for _, frame := range frames {
+		if frame.Height() == 0 {
+			continue
+		}
+		for _, col := range frame.Series {
+			dtype, ok := dtypes[col.Name]
+			if !ok || (dtype.ID() == arrow.INT64 && col.Type() == arrow.FLOAT64) {
+				dtypes[col.Name] = col.DataType()
+			}
+		}
+	}
+
+	for i, frame := range frames {
+		casts := make(map[string]arrow.DataType)
+		for _, col := range frame.Series {
+			dtype, ok := dtypes[col.Name]
+			if !ok || arrow.TypeEqual(dtype, col.DataType()) {
+				continue
+			}
+			if frame.Height() == 0 || (col.Type() == arrow.INT64 && dtype.ID() == arrow.FLOAT64) {
+				casts[col.Name] = dtype
+			}
+		}
+		if len(casts) == 0 {
+			continue
+		}
+		var unified *dataframe.DataFrame
+		if frame.Height() == 0 {
+			cols := make([]series.Series, len(frame.Series))
+			for j, col := range frame.Series {
+				if dtype, ok := casts[col.Name]; ok {
+					cols[j] = series.NewEmptySeries(col.Name, dtype)
+				} else {
+					col.Retain()
+					cols[j] = col
+				}
+			}
+			unified = dataframe.NewDataFrame(cols)
+		} else {
+			var err error
+			if unified, err = frame.CastColumns(casts); err != nil {
+				return err
+			}
+		}
+		frame.Release()
+		frames[i] = unified
+	}
+	return nil
+}
+
+// OpenDataset lists the files matching the glob pattern, which are read with read, see ReadGlob.
+func OpenDataset(pattern string, read ReadFileFunc) (*Dataset, error) {
+	// The directory before the first wildcard is the root of an fs.FS, where the rest of the pattern is globbed.
+	dir := filepath.Dir(pattern)
+	for strings.ContainsAny(dir, "*?[") {
+		dir = filepath.Dir(dir)
+	}
+	rel, err := filepath.Rel(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := OpenDatasetFS(os.DirFS(dir), filepath.ToSlash(rel), func(_ fs.FS, path string) (*dataframe.DataFrame, error) {
+		return read(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range ds.paths {
+		ds.paths[i] = filepath.Join(dir, filepath.FromSlash(name))
+	}
+	return ds, nil
+}
+
+// OpenDatasetFS lists the files of a file system matching the glob pattern, which are read with read, see ReadGlob.
+func OpenDatasetFS(fsys fs.FS, pattern string, read ReadFSFunc) (*Dataset, error) {
+	paths, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	keys, partitions, err := parseHivePartitions(globRoot(pattern), paths)
+	if err != nil {
+		return nil, err
+	}
+	return &Dataset{fsys: fsys, read: read, paths: paths, keys: keys, partitions: partitions}, nil
+}
+
+// Files returns the paths of the files of the dataset, in order.
+func (ds *Dataset) Files() []string {
+	return append([]string{}, ds.paths...)
+}
+
+// PartitionKeys returns the names of the partition columns of the dataset.
+func (ds *Dataset) PartitionKeys() []string {
+	return append([]string{}, ds.keys...)
+}
+
+// PartitionFilter reports whether the files of a partition should be read,
+// from the values of its partition keys, where nulls are not valid.
+type PartitionFilter func(partition map[string]primitive.Optional[string]) bool
+
+// Scan reads the files of the dataset whose partition satisfies filter, or every file if filter is nil,
+// and concatenates them like ReadGlob. It returns an error if no file satisfies the filter.
+func (ds *Dataset) Scan(filter PartitionFilter) (*dataframe.DataFrame, error) {
+	var paths []string
+	var partitions [][]primitive.Optional[string]
+	for i, path := range ds.paths {
+		if filter != nil {
+			values := make(map[string]primitive.Optional[string], len(ds.keys))
+			for k, key := range ds.keys {
+				values[key] = ds.partitions[i][k]
+			}
+			if !filter(values) {
+				continue
+			}
+		}
+		paths = append(paths, path)
+		partitions = append(partitions, ds.partitions[i])
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files of the dataset match the filter")
+	}
+
+	frames, err := readFiles(ds.fsys, paths, ds.read)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, frame := range frames {
+			frame.Release()
+		}
+	}()
+	if err := unifyFrames(frames); err != nil {
+		return nil, err
+	}
+	df, err := dataframe.Concat(frames, dataframe.ConcatVertical)
+	if err != nil {
+		return nil, err
+	}
+	defer df.Release()
+
+	partitionCols := make([]*series.Series, len(ds.keys))
+	defer func() {
+		for _, col := range partitionCols {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+	for k, key := range ds.keys {
+		if _, err := df.Column(key); err == nil {
+			return nil, fmt.Errorf("partition key %s is also a column of the files", key)
+		}
+		var vals []interface{}
+		var valid []bool
+		for i, frame := range frames {
+			var val interface{} = primitive.Null{}
+			v := partitions[i][k]
+			if v.Valid {
+				val = v.Value
+			}
+			for j := 0; j < frame.Height(); j++ {
+				vals = append(vals, val)
+				valid = append(valid, v.Valid)
+			}
+		}
+		col := series.NewEmptySeries(key, arrow.BinaryTypes.String)
+		if len(vals) > 0 {
+			col.Release()
+			var err error
+			if col, err = series.NewSeriesFromSliceChecked(key, vals, valid, true); err != nil {
+				return nil, fmt.Errorf("partition %s: %w", key, err)
+			}
+		}
+		partitionCols[k] = &col
+	}
+	return df.WithColumns(partitionCols...), nil
+}
+
+// ReadCsvGlob reads every CSV file matching the glob pattern and returns a DataFrame, see ReadGlob.
+func ReadCsvGlob(pattern string) (*dataframe.DataFrame, error) {
+	return ReadGlob(pattern, ReadCsvFile)
+}
+
+// ReadCsvGlobFS reads every CSV file of a file system matching the glob pattern and returns a DataFrame, see ReadGlob.
+func ReadCsvGlobFS(fsys fs.FS, pattern string) (*dataframe.DataFrame, error) {
+	return ReadGlobFS(fsys, pattern, ReadCsvFS)
+}
+
+// ReadParquetGlob reads every parquet file matching the glob pattern and returns a DataFrame, see ReadGlob.
+func ReadParquetGlob(pattern string) (*dataframe.DataFrame, error) {
+	return ReadGlob(pattern, ReadParquetFile)
+}
+
+// ReadParquetGlobFS reads every parquet file of a file system matching the glob pattern and returns a DataFrame, see ReadGlob.
+func ReadParquetGlobFS(fsys fs.FS, pattern string) (*dataframe.DataFrame, error) {
+	return ReadGlobFS(fsys, pattern, ReadParquetFS)
+}
+
+// readFiles reads the files concurrently, with at most GOMAXPROCS files at a time.
+// Once a file fails, the files that are not being read yet are skipped.
+func readFiles(fsys fs.FS, paths []string, read ReadFSFunc) ([]*dataframe.DataFrame, error) {
+	frames := make([]*dataframe.DataFrame, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		sem <- struct{}{}
+		if failed.Load() {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			frames[i], errs[i] = read(fsys, path)
+			if errs[i] != nil {
+				failed.Store(true)
+			}
+		}(i, path)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			for _, frame := range frames {
+				if frame != nil {
+					frame.Release()
+				}
+			}
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+	}
+	return frames, nil
+}
+
+// globRoot returns the directory of the pattern before its first wildcard.
+func globRoot(pattern string) string {
+	root := path.Dir(filepath.ToSlash(pattern))
+	for strings.ContainsAny(root, "*?[") {
+		root = path.Dir(root)
+	}
+	return root
+}
+
+// parseHivePartitions parses the key=value directories of every path below root.
+// It returns the partition keys, and the value of every key for every path.
+func parseHivePartitions(root string, paths []string) ([]string, [][]primitive.Optional[string], error) {
+	var keys []string
+	partitions := make([][]primitive.Optional[string], len(paths))
+	for i, name := range paths {
+		rel := path.Dir(filepath.ToSlash(name))
+		if root != "." {
+			rel = strings.TrimPrefix(rel, strings.TrimSuffix(root, "/")+"/")
+		}
+		var pathKeys []string
+		for _, dir := range strings.Split(rel, "/") {
+			key, value, ok := strings.Cut(dir, "=")
+			if !ok || key == "" {
+				continue
+			}
+			pathKeys = append(pathKeys, key)
+			if value == hiveNullPartition {
+				partitions[i] = append(partitions[i], primitive.None[string]())
+				continue
+			}
+			unescaped, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+			partitions[i] = append(partitions[i], primitive.Some(unescaped))
+		}
+		if i == 0 {
+			keys = pathKeys
+		} else if strings.Join(pathKeys, "/") != strings.Join(keys, "/") {
+			return nil, nil, fmt.Errorf("%s is partitioned by %v, expected %v", name, pathKeys, keys)
+		}
+	}
+	return keys, partitions, nil
+}
+
+// WriteParquetPartitioned writes a DataFrame as a Hive-partitioned parquet dataset in dir.
+// The rows of every distinct combination of values of the partitionBy columns are written,
+// without those columns, to dir/key1=value1/key2=value2/part-0.parquet, replacing any existing file.
+// Null values are written as __HIVE_DEFAULT_PARTITION__.
+func WriteParquetPartitioned(df *dataframe.DataFrame, dir string, partitionBy ...string) error {
+	return WriteParquetPartitionedFS(LocalFS(dir), df, ".", partitionBy...)
+}
+
+// WriteParquetPartitionedFS writes a DataFrame as a Hive-partitioned parquet dataset
+// in the named directory of a file system, see WriteParquetPartitioned.
+func WriteParquetPartitionedFS(fsys WriteFS, df *dataframe.DataFrame, dir string, partitionBy ...string) error {
+	if len(partitionBy) == 0 {
+		return fmt.Errorf("no partition columns")
+	}
+	keyCols, err := df.Select(partitionBy...)
+	if err != nil {
+		return err
+	}
+	defer keyCols.Release()
+	data, err := df.Drop(partitionBy...)
+	if err != nil {
+		return err
+	}
+	defer data.Release()
+	partitions := partition.Rows(keyCols.Series, df.Height())
+
+	for _, rows := range partitions {
+		partitionDir := dir
+		for _, col := range keyCols.Series {
+			value := hiveNullPartition
+			if v := col.ValueExn(int(rows[0])); v.Valid {
+				value = url.PathEscape(fmt.Sprint(v.Value))
+			}
+			partitionDir = path.Join(partitionDir, col.Name+"="+value)
+		}
+		if err := fsys.MkdirAll(partitionDir); err != nil {
+			return err
+		}
+
+		indices := series.NewSeriesTFromTSlice("", rows, nil)
+		part, err := data.Take(&indices)
+		indices.Release()
+		if err != nil {
+			return err
+		}
+		err = WriteParquetFS(fsys, part, path.Join(partitionDir, "part-0.parquet"))
+		part.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
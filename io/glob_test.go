@@ -0,0 +1,155 @@
+package io_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/io"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func TestReadCsvGlob(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"year=2022/month=12/data.csv":                         "id,value\n1,a\n2,b\n",
+		"year=2023/month=1/data.csv":                          "id,value\n3,c\n",
+		"year=2023/month=__HIVE_DEFAULT_PARTITION__/data.csv": "id,value\n4,d\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	df, err := io.ReadCsvGlob(filepath.Join(dir, "year=*", "month=*", "*.csv"))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "value", "year", "month"}, df.GetColumnNames())
+	id, _ := df.Column("id")
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(3), int64(4)}, columnValues(id))
+	year, _ := df.Column("year")
+	assert.Equal(t, arrow.INT64, year.Type())
+	assert.DeepEqual(t, []interface{}{int64(2022), int64(2022), int64(2023), int64(2023)}, columnValues(year))
+	month, _ := df.Column("month")
+	assert.DeepEqual(t, []interface{}{int64(12), int64(12), int64(1), nil}, columnValues(month))
+
+	_, err = io.ReadCsvGlob(filepath.Join(dir, "missing", "*.csv"))
+	assert.Error(t, err)
+	_, err = io.ReadCsvGlob(filepath.Join(dir, "*", "*", "*.csv"))
+	assert.NoError(t, err)
+}
+
+func TestReadGlobMismatchedPartitions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a=1/data.csv", "b=1/data.csv"} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte("id\n1\n"), 0o644))
+	}
+	_, err := io.ReadCsvGlob(filepath.Join(dir, "*", "*.csv"))
+	assert.Error(t, err)
+}
+
+func TestDatasetScan(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"year=2022/data.csv": "id\n1\n2\n",
+		"year=2023/data.csv": "id\n3\n",
+		"year=2024/data.csv": "not,a\nvalid,file,at all\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	ds, err := io.OpenDataset(filepath.Join(dir, "year=*", "*.csv"), io.ReadCsvFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(ds.Files()))
+	assert.DeepEqual(t, []string{"year"}, ds.PartitionKeys())
+
+	// The invalid file of 2024 is never read.
+	df, err := ds.Scan(func(partition map[string]primitive.Optional[string]) bool {
+		return partition["year"].Value != "2024"
+	})
+	assert.NoError(t, err)
+	defer df.Release()
+	id, _ := df.Column("id")
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(3)}, columnValues(id))
+	year, _ := df.Column("year")
+	assert.DeepEqual(t, []interface{}{int64(2022), int64(2022), int64(2023)}, columnValues(year))
+
+	_, err = ds.Scan(func(map[string]primitive.Optional[string]) bool { return false })
+	assert.Error(t, err)
+	_, err = ds.Scan(nil)
+	assert.Error(t, err)
+}
+
+func TestDatasetScanUnifiesTypes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.csv": "x,y\n10,a\n",
+		"b.csv": "x,y\n",
+		"c.csv": "x,y\n2.5,b\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	df, err := io.ReadCsvGlob(filepath.Join(dir, "*.csv"))
+	assert.NoError(t, err)
+	defer df.Release()
+	assert.Equal(t, 2, df.Height())
+	x, _ := df.Column("x")
+	assert.Equal(t, arrow.FLOAT64, x.Type())
+	assert.DeepEqual(t, []interface{}{10.0, 2.5}, columnValues(x))
+	y, _ := df.Column("y")
+	assert.DeepEqual(t, []interface{}{"a", "b"}, columnValues(y))
+}
+
+func TestReadGlobStopsOnError(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	fsys := fstest.MapFS{}
+	for _, name := range []string{"a.csv", "b.csv", "c.csv"} {
+		fsys[name] = &fstest.MapFile{Data: []byte("id\n1\n")}
+	}
+	reads := 0
+	_, err := io.ReadGlobFS(fsys, "*.csv", func(fsys fs.FS, name string) (*dataframe.DataFrame, error) {
+		reads++
+		return nil, errors.New("failed")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, reads)
+}
+
+func TestWriteParquetPartitioned(t *testing.T) {
+	df := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("id", []int64{1, 2, 3, 4}),
+		series.NewSeries("region", []string{"eu", "us", "eu", "us/east"}),
+		series.NewSeries("score", []float64{1.5, 2.5, 3.5, 4.5}),
+	})
+	dir := t.TempDir()
+	assert.NoError(t, io.WriteParquetPartitioned(df, dir, "region"))
+	_, err := os.Stat(filepath.Join(dir, "region=us%2Feast", "part-0.parquet"))
+	assert.NoError(t, err)
+
+	out, err := io.ReadParquetGlob(filepath.Join(dir, "region=*", "*.parquet"))
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "score", "region"}, out.GetColumnNames())
+	id, _ := out.Column("id")
+	assert.DeepEqual(t, []interface{}{int64(1), int64(3), int64(2), int64(4)}, columnValues(id))
+	region, _ := out.Column("region")
+	assert.DeepEqual(t, []interface{}{"eu", "eu", "us", "us/east"}, columnValues(region))
+
+	assert.Error(t, io.WriteParquetPartitioned(df, dir))
+	assert.Error(t, io.WriteParquetPartitioned(df, dir, "missing"))
+}
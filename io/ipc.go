@@ -0,0 +1,255 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/ipc"
+)
+
+// IPCFormat is the layout of Arrow IPC data.
+type IPCFormat int
+
+const (
+	// IPCFileFormat is the Arrow IPC file format, also known as Feather v2.
+	// It supports random access to its record batches.
+	IPCFileFormat IPCFormat = iota
+	// IPCStreamFormat is the Arrow IPC streaming format, which can be read and written sequentially.
+	IPCStreamFormat
+)
+
+// IPCCompression is the compression codec applied to the buffers of Arrow IPC data.
+type IPCCompression int
+
+const (
+	// IPCUncompressed leaves the buffers uncompressed.
+	IPCUncompressed IPCCompression = iota
+	// IPCLZ4 compresses the buffers with the LZ4 frame format.
+	IPCLZ4
+	// IPCZstd compresses the buffers with Zstandard.
+	IPCZstd
+)
+
+// IPCOptions configures how a DataFrame is written as Arrow IPC data.
+// The zero value writes an uncompressed IPC file.
+type IPCOptions struct {
+	Format      IPCFormat
+	Compression IPCCompression
+}
+
+// ipcMagic is the string that starts and ends the Arrow IPC file format.
+var ipcMagic = []byte("ARROW1")
+
+// ReadIPC reads Arrow IPC data from an io.Reader and returns a DataFrame.
+// Both the file and the stream formats are accepted, compressed or not.
+// Every record batch becomes a chunk of the columns.
+func ReadIPC(input io.Reader) (*dataframe.DataFrame, error) {
+	if r, ok := input.(ipc.ReadAtSeeker); ok {
+		magic := make([]byte, len(ipcMagic))
+		if _, err := r.ReadAt(magic, 0); err == nil && bytes.Equal(magic, ipcMagic) {
+			return readIPCFile(r)
+		}
+		return readIPCStream(input)
+	}
+
+	buffered := bufio.NewReader(input)
+	magic, err := buffered.Peek(len(ipcMagic))
+	if err == nil && bytes.Equal(magic, ipcMagic) {
+		// The file format needs random access, so it is read into memory first.
+		data, err := io.ReadAll(buffered)
+		if err != nil {
+			return nil, err
+		}
+		return readIPCFile(bytes.NewReader(data))
+	}
+	return readIPCStream(buffered)
+}
+
+// ReadIPCFile reads an Arrow IPC file, or a file holding an Arrow IPC stream, from a path and returns a DataFrame.
+// Files with the extension of a compression format are decompressed, see CompressionFromPath.
+//
+// Other files are memory-mapped, and the columns of an uncompressed IPC file share the mapped memory
+// instead of copying it, so that only the pages that are used are loaded from disk.
+// The file is unmapped once the DataFrame, and every Series sharing its columns, is released,
+// and must not be modified or truncated until then.
+// Compressed record batches, dictionaries, unions and extension types are copied into memory as in ReadIPC.
+func ReadIPCFile(path string) (*dataframe.DataFrame, error) {
+	if CompressionFromPath(path) != NoCompression {
+		fsys, name := localFile(path)
+		return ReadIPCFS(fsys, name)
+	}
+	buf, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// The DataFrame retains the mapping while it uses it.
+	defer buf.Release()
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, ipcMagic) {
+		return readIPCStream(bytes.NewReader(data))
+	}
+	df, err := readMappedIPCFile(buf)
+	if errors.Is(err, errNotMappable) {
+		return readIPCFile(bytes.NewReader(data))
+	}
+	return df, err
+}
+
+// ReadIPCFS reads the named file of a file system holding Arrow IPC data, and returns a DataFrame.
+// Files with the extension of a compression format are decompressed, see CompressionFromPath.
+func ReadIPCFS(fsys fs.FS, name string) (*dataframe.DataFrame, error) {
+	var file io.ReadCloser
+	var err error
+	if CompressionFromPath(name) != NoCompression {
+		file, err = openFile(fsys, name)
+	} else {
+		// Uncompressed files are passed as is, so ReadIPC can use their random access if they have it.
+		file, err = fsys.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadIPC(file)
+}
+
+func readIPCFile(r ipc.ReadAtSeeker) (*dataframe.DataFrame, error) {
+	rdr, err := ipc.NewFileReader(r, ipc.WithAllocator(series.DefaultAllocator()))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	recs := make([]arrow.Record, 0, rdr.NumRecords())
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+	for i := 0; i < rdr.NumRecords(); i++ {
+		rec, err := rdr.RecordAt(i)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return dataframe.FromRecords(rdr.Schema(), recs)
+}
+
+func readIPCStream(r io.Reader) (*dataframe.DataFrame, error) {
+	rdr, err := ipc.NewReader(r, ipc.WithAllocator(series.DefaultAllocator()))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	var recs []arrow.Record
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+	for rdr.Next() {
+		rec := rdr.Record()
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+	if err := rdr.Err(); err != nil {
+		return nil, err
+	}
+	return dataframe.FromRecords(rdr.Schema(), recs)
+}
+
+// WriteIPC writes a DataFrame as Arrow IPC data to an io.Writer.
+// Every chunk of the DataFrame becomes a record batch.
+func WriteIPC(output io.Writer, df *dataframe.DataFrame, opts IPCOptions) error {
+	recs, err := df.ToRecordBatches()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+
+	ipcOpts := []ipc.Option{ipc.WithSchema(df.Schema())}
+	switch opts.Compression {
+	case IPCUncompressed:
+	case IPCLZ4:
+		ipcOpts = append(ipcOpts, ipc.WithLZ4())
+	case IPCZstd:
+		ipcOpts = append(ipcOpts, ipc.WithZstd())
+	default:
+		return fmt.Errorf("unknown IPC compression %d", opts.Compression)
+	}
+
+	var w interface {
+		Write(arrow.Record) error
+		Close() error
+	}
+	switch opts.Format {
+	case IPCFileFormat:
+		fw, err := ipc.NewFileWriter(&positionWriter{w: output}, ipcOpts...)
+		if err != nil {
+			return err
+		}
+		w = fw
+	case IPCStreamFormat:
+		w = ipc.NewWriter(output, ipcOpts...)
+	default:
+		return fmt.Errorf("unknown IPC format %d", opts.Format)
+	}
+
+	for _, rec := range recs {
+		if err := w.Write(rec); err != nil {
+			w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// WriteIPCFile writes a DataFrame as Arrow IPC data to a file.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteIPCFile(df *dataframe.DataFrame, path string, opts IPCOptions) error {
+	fsys, name := localFile(path)
+	return WriteIPCFS(fsys, df, name, opts)
+}
+
+// WriteIPCFS writes a DataFrame as Arrow IPC data to the named file of a file system.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteIPCFS(fsys WriteFS, df *dataframe.DataFrame, name string, opts IPCOptions) error {
+	return writeFile(fsys, name, func(w io.Writer) error {
+		return WriteIPC(w, df, opts)
+	})
+}
+
+// positionWriter tracks the number of bytes written to w.
+// The IPC file writer only seeks to find its current position,
+// so this lets it write to any io.Writer.
+type positionWriter struct {
+	w   io.Writer
+	pos int64
+}
+
+func (p *positionWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.pos += int64(n)
+	return n, err
+}
+
+func (p *positionWriter) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekCurrent {
+		return 0, errors.New("positionWriter can only report its current position")
+	}
+	return p.pos, nil
+}
@@ -0,0 +1,323 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/ipc"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// errNotMappable is returned by readMappedIPCFile for files whose record batches cannot be used in place,
+// because they are compressed, or hold dictionaries or types that are not handled by ipcRecordLoader.
+var errNotMappable = errors.New("arrow/ipc: record batches cannot be read in place")
+
+// Slots of the fields of the flatbuffers tables of the Arrow IPC format, see File.fbs and Message.fbs in the Arrow repository.
+const (
+	footerRecordBatches    = 10
+	messageVersion         = 4
+	messageHeaderType      = 6
+	messageHeader          = 8
+	recordBatchLength      = 4
+	recordBatchNodes       = 6
+	recordBatchBuffers     = 8
+	recordBatchCompression = 10
+)
+
+const (
+	// messageRecordBatch is the type of RecordBatch headers in the MessageHeader union.
+	messageRecordBatch = 3
+	// blockSize, fieldNodeSize and bufferSize are the sizes of the Block, FieldNode and Buffer structs.
+	blockSize     = 24
+	fieldNodeSize = 16
+	bufferSize    = 16
+)
+
+// readMappedIPCFile reads the Arrow IPC file held by buf without copying the buffers of its record batches:
+// the columns of the returned DataFrame are slices of buf, which is retained until they are all released.
+// It returns errNotMappable for files that have to be decoded by readIPCFile instead.
+func readMappedIPCFile(buf *memory.Buffer) (df *dataframe.DataFrame, err error) {
+	data := buf.Bytes()
+	// The file reader only decodes the footer and the schema, the record batches are read below.
+	rdr, err := ipc.NewFileReader(bytes.NewReader(data), ipc.WithAllocator(series.DefaultAllocator()))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	schema := rdr.Schema()
+	if rdr.NumDictionaries() > 0 || rdr.Version() != ipc.MetadataV5 || !schema.IsNativeEndian() {
+		return nil, errNotMappable
+	}
+	for _, field := range schema.Fields() {
+		if !mappableType(field.Type) {
+			return nil, errNotMappable
+		}
+	}
+
+	recs := make([]arrow.Record, 0, rdr.NumRecords())
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+	// The flatbuffers accessors panic on offsets outside of a corrupted file.
+	defer func() {
+		if r := recover(); r != nil {
+			df, err = nil, fmt.Errorf("arrow/ipc: invalid file: %v", r)
+		}
+	}()
+
+	loaders, err := ipcRecordLoaders(buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range loaders {
+		rec, err := l.record(schema)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return dataframe.FromRecords(schema, recs)
+}
+
+// mappableType reports whether the arrays of the given type can be read in place by ipcRecordLoader.
+func mappableType(dtype arrow.DataType) bool {
+	switch dtype := dtype.(type) {
+	case *arrow.NullType, *arrow.BooleanType,
+		*arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type,
+		*arrow.Float16Type, *arrow.Float32Type, *arrow.Float64Type,
+		*arrow.Decimal128Type, *arrow.Decimal256Type,
+		*arrow.Time32Type, *arrow.Time64Type, *arrow.TimestampType,
+		*arrow.Date32Type, *arrow.Date64Type, *arrow.DurationType,
+		*arrow.MonthIntervalType, *arrow.DayTimeIntervalType, *arrow.MonthDayNanoIntervalType,
+		*arrow.FixedSizeBinaryType,
+		*arrow.BinaryType, *arrow.StringType, *arrow.LargeBinaryType, *arrow.LargeStringType:
+		return true
+	case *arrow.ListType:
+		return mappableType(dtype.Elem())
+	case *arrow.LargeListType:
+		return mappableType(dtype.Elem())
+	case *arrow.FixedSizeListType:
+		return mappableType(dtype.Elem())
+	case *arrow.MapType:
+		return mappableType(dtype.ValueType())
+	case *arrow.StructType:
+		for _, field := range dtype.Fields() {
+			if !mappableType(field.Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// ipcRecordLoaders returns a loader for every record batch of the Arrow IPC file held by buf,
+// listed in the footer of the file.
+func ipcRecordLoaders(buf *memory.Buffer) ([]*ipcRecordLoader, error) {
+	data := buf.Bytes()
+	// The file ends with its footer, the length of the footer and the magic string.
+	end := len(data) - len(ipcMagic) - 4
+	if end < len(ipcMagic) {
+		return nil, errors.New("arrow/ipc: file is too small")
+	}
+	size := int(int32(binary.LittleEndian.Uint32(data[end:])))
+	if size <= 0 || size > end-len(ipcMagic) {
+		return nil, fmt.Errorf("arrow/ipc: invalid footer length %d", size)
+	}
+	footer := data[end-size : end]
+	tab := flatbuffers.Table{Bytes: footer, Pos: flatbuffers.GetUOffsetT(footer)}
+
+	o := flatbuffers.UOffsetT(tab.Offset(footerRecordBatches))
+	if o == 0 {
+		return nil, nil
+	}
+	start := tab.Vector(o)
+	loaders := make([]*ipcRecordLoader, tab.VectorLen(o))
+	for i := range loaders {
+		block := start + flatbuffers.UOffsetT(i*blockSize)
+		offset := int(tab.GetInt64(block))
+		metaLen := int(tab.GetInt32(block + 8))
+		bodyLen := int(tab.GetInt64(block + 16))
+		if offset < 0 || metaLen < 8 || bodyLen < 0 || offset+metaLen+bodyLen > len(data) {
+			return nil, fmt.Errorf("arrow/ipc: record batch %d is outside of the file", i)
+		}
+		batch, err := ipcRecordBatch(data[offset : offset+metaLen])
+		if err != nil {
+			return nil, fmt.Errorf("record batch %d: %w", i, err)
+		}
+		loaders[i] = &ipcRecordLoader{buf: buf, batch: batch, body: offset + metaLen, bodyLen: bodyLen}
+	}
+	return loaders, nil
+}
+
+// ipcRecordBatch returns the RecordBatch header of an encapsulated message.
+func ipcRecordBatch(meta []byte) (flatbuffers.Table, error) {
+	// The metadata starts with a continuation marker followed by its length, or only its length for old files.
+	if binary.LittleEndian.Uint32(meta) == 0xFFFFFFFF {
+		meta = meta[8:]
+	} else {
+		meta = meta[4:]
+	}
+	msg := flatbuffers.Table{Bytes: meta, Pos: flatbuffers.GetUOffsetT(meta)}
+	if msg.GetInt16Slot(messageVersion, 0) != int16(ipc.MetadataV5) {
+		return flatbuffers.Table{}, errNotMappable
+	}
+	if msg.GetByteSlot(messageHeaderType, 0) != messageRecordBatch {
+		return flatbuffers.Table{}, errors.New("arrow/ipc: message is not a record batch")
+	}
+	var batch flatbuffers.Table
+	o := flatbuffers.UOffsetT(msg.Offset(messageHeader))
+	if o == 0 {
+		return flatbuffers.Table{}, errors.New("arrow/ipc: record batch without header")
+	}
+	msg.Union(&batch, o)
+	if batch.Offset(recordBatchCompression) != 0 {
+		return flatbuffers.Table{}, errNotMappable
+	}
+	return batch, nil
+}
+
+// ipcRecordLoader builds the arrays of a record batch from the field nodes and the buffers of its header,
+// which are listed depth-first in the order of the columns.
+// The buffers of the arrays are slices of buf, the body of the record batch starting at the offset body.
+type ipcRecordLoader struct {
+	buf     *memory.Buffer
+	batch   flatbuffers.Table
+	body    int
+	bodyLen int
+	node    int
+	buffer  int
+}
+
+// record builds the record batch with the given schema.
+func (l *ipcRecordLoader) record(schema *arrow.Schema) (arrow.Record, error) {
+	cols := make([]arrow.Array, 0, len(schema.Fields()))
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for _, field := range schema.Fields() {
+		data, err := l.load(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", field.Name, err)
+		}
+		cols = append(cols, array.MakeFromData(data))
+		data.Release()
+	}
+	return array.NewRecord(schema, cols, l.batch.GetInt64Slot(recordBatchLength, 0)), nil
+}
+
+// load builds the next array, of the given type.
+func (l *ipcRecordLoader) load(dtype arrow.DataType) (arrow.ArrayData, error) {
+	length, nulls, err := l.nextNode()
+	if err != nil {
+		return nil, err
+	}
+	if dtype.ID() == arrow.NULL {
+		return array.NewData(dtype, length, nil, nil, nulls, 0), nil
+	}
+
+	// Every other array starts with its validity bitmap, which may be left out when there are no nulls.
+	var buffers []*memory.Buffer
+	defer func() {
+		for _, buf := range buffers {
+			if buf != nil {
+				buf.Release()
+			}
+		}
+	}()
+	validity, err := l.nextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if nulls == 0 {
+		validity.Release()
+		validity = nil
+	}
+	buffers = append(buffers, validity)
+
+	// The values, or the offsets of variable length and list types, follow the bitmap.
+	nbuffers := 1
+	var children []arrow.DataType
+	switch dtype := dtype.(type) {
+	case *arrow.BinaryType, *arrow.StringType, *arrow.LargeBinaryType, *arrow.LargeStringType:
+		nbuffers = 2
+	case *arrow.ListType:
+		children = []arrow.DataType{dtype.Elem()}
+	case *arrow.LargeListType:
+		children = []arrow.DataType{dtype.Elem()}
+	case *arrow.MapType:
+		children = []arrow.DataType{dtype.ValueType()}
+	case *arrow.FixedSizeListType:
+		nbuffers = 0
+		children = []arrow.DataType{dtype.Elem()}
+	case *arrow.StructType:
+		nbuffers = 0
+		for _, field := range dtype.Fields() {
+			children = append(children, field.Type)
+		}
+	}
+	for i := 0; i < nbuffers; i++ {
+		buf, err := l.nextBuffer()
+		if err != nil {
+			return nil, err
+		}
+		buffers = append(buffers, buf)
+	}
+
+	childData := make([]arrow.ArrayData, 0, len(children))
+	defer func() {
+		for _, child := range childData {
+			child.Release()
+		}
+	}()
+	for _, child := range children {
+		data, err := l.load(child)
+		if err != nil {
+			return nil, err
+		}
+		childData = append(childData, data)
+	}
+	return array.NewData(dtype, length, buffers, childData, nulls, 0), nil
+}
+
+// nextNode returns the length and the null count of the next array.
+func (l *ipcRecordLoader) nextNode() (length, nulls int, err error) {
+	o := flatbuffers.UOffsetT(l.batch.Offset(recordBatchNodes))
+	if o == 0 || l.node >= l.batch.VectorLen(o) {
+		return 0, 0, errors.New("arrow/ipc: missing field node")
+	}
+	node := l.batch.Vector(o) + flatbuffers.UOffsetT(l.node*fieldNodeSize)
+	l.node++
+	return int(l.batch.GetInt64(node)), int(l.batch.GetInt64(node + 8)), nil
+}
+
+// nextBuffer returns the next buffer of the body, as a slice of l.buf.
+func (l *ipcRecordLoader) nextBuffer() (*memory.Buffer, error) {
+	o := flatbuffers.UOffsetT(l.batch.Offset(recordBatchBuffers))
+	if o == 0 || l.buffer >= l.batch.VectorLen(o) {
+		return nil, errors.New("arrow/ipc: missing buffer")
+	}
+	pos := l.batch.Vector(o) + flatbuffers.UOffsetT(l.buffer*bufferSize)
+	l.buffer++
+	offset, length := int(l.batch.GetInt64(pos)), int(l.batch.GetInt64(pos+8))
+	if length == 0 {
+		return memory.NewBufferBytes(nil), nil
+	}
+	if offset < 0 || length < 0 || offset+length > l.bodyLen {
+		return nil, fmt.Errorf("arrow/ipc: buffer of %d bytes at %d is outside of the body of %d bytes", length, offset, l.bodyLen)
+	}
+	return memory.SliceBuffer(l.buf, l.body+offset, length), nil
+}
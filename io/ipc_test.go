@@ -0,0 +1,134 @@
+package io_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/memtest"
+	"github.com/kstremick/mango/io"
+
+	"github.com/zeebo/assert"
+)
+
+func ipcTestDataFrame(t *testing.T) *dataframe.DataFrame {
+	a := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("id", []int64{1, 2, 3}),
+		series.NewSeries("name", []string{"a", "b", "c"}),
+		series.NewSeriesFromSlice("score", []float64{1.5, 0, 3.5}, []bool{true, false, true}, false),
+	})
+	b := dataframe.NewDataFrame([]series.Series{
+		series.NewSeries("id", []int64{4}),
+		series.NewSeries("name", []string{"d"}),
+		series.NewSeries("score", []float64{4.5}),
+	})
+	df, err := dataframe.Concat([]*dataframe.DataFrame{a, b}, dataframe.ConcatVertical)
+	assert.NoError(t, err)
+	return df
+}
+
+func TestIPCRoundTrip(t *testing.T) {
+	type testCase struct {
+		name string
+		opts io.IPCOptions
+	}
+	testCases := []testCase{
+		{"file", io.IPCOptions{}},
+		{"file lz4", io.IPCOptions{Compression: io.IPCLZ4}},
+		{"file zstd", io.IPCOptions{Compression: io.IPCZstd}},
+		{"stream", io.IPCOptions{Format: io.IPCStreamFormat}},
+		{"stream lz4", io.IPCOptions{Format: io.IPCStreamFormat, Compression: io.IPCLZ4}},
+		{"stream zstd", io.IPCOptions{Format: io.IPCStreamFormat, Compression: io.IPCZstd}},
+	}
+
+	df := ipcTestDataFrame(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, io.WriteIPC(&buf, df, tc.opts))
+
+			// Read through a plain io.Reader, which hides the random access of the buffer.
+			out, err := io.ReadIPC(struct{ *bytes.Buffer }{bytes.NewBuffer(buf.Bytes())})
+			assert.NoError(t, err)
+			assert.Equal(t, df.String(), out.String())
+			assert.DeepEqual(t, []int{3, 1}, out.Series[0].ChunkLengths())
+
+			out, err = io.ReadIPC(bytes.NewReader(buf.Bytes()))
+			assert.NoError(t, err)
+			assert.Equal(t, df.String(), out.String())
+		})
+	}
+}
+
+func TestIPCFile(t *testing.T) {
+	df := ipcTestDataFrame(t)
+	for _, format := range []io.IPCFormat{io.IPCFileFormat, io.IPCStreamFormat} {
+		path := filepath.Join(t.TempDir(), "data.arrow")
+		assert.NoError(t, io.WriteIPCFile(df, path, io.IPCOptions{Format: format, Compression: io.IPCZstd}))
+		out, err := io.ReadIPCFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, df.String(), out.String())
+	}
+
+	_, err := io.ReadIPCFile(filepath.Join(t.TempDir(), "missing.arrow"))
+	assert.Error(t, err)
+}
+
+func TestIPCFileMapped(t *testing.T) {
+	data := `{"id": 1, "name": "a", "score": 1.5, "ok": true, "tags": ["x", null], "user": {"age": 30}}
+{"id": 2, "name": null, "score": null, "ok": false, "tags": [], "user": null}
+{"id": 3, "name": "", "score": 3.5, "ok": null, "tags": null, "user": {"age": null}}
+`
+	df, err := io.ReadNDJSON(strings.NewReader(data), io.JSONOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	var expected bytes.Buffer
+	assert.NoError(t, io.WriteNDJSON(&expected, df))
+
+	type testCase struct {
+		name   string
+		opts   io.IPCOptions
+		mapped bool
+	}
+	testCases := []testCase{
+		{"uncompressed", io.IPCOptions{}, true},
+		{"compressed", io.IPCOptions{Compression: io.IPCLZ4}, false},
+		{"stream", io.IPCOptions{Format: io.IPCStreamFormat}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.arrow")
+			assert.NoError(t, io.WriteIPCFile(df, path, tc.opts))
+
+			mem := memtest.CheckedAllocator(t)
+			out, err := io.ReadIPCFile(path)
+			assert.NoError(t, err)
+			defer out.Release()
+			// The columns of mapped files share the memory of the mapping instead of allocating their own.
+			assert.Equal(t, tc.mapped, mem.CurrentAlloc() == 0)
+			assert.DeepEqual(t, []int{2, 1}, out.Series[0].ChunkLengths())
+
+			var buf bytes.Buffer
+			assert.NoError(t, io.WriteNDJSON(&buf, out))
+			assert.Equal(t, expected.String(), buf.String())
+		})
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.arrow")
+	assert.NoError(t, os.WriteFile(empty, nil, 0o644))
+	_, err = io.ReadIPCFile(empty)
+	assert.Error(t, err)
+}
+
+func TestIPCInvalid(t *testing.T) {
+	df := ipcTestDataFrame(t)
+	var buf bytes.Buffer
+	assert.Error(t, io.WriteIPC(&buf, df, io.IPCOptions{Format: io.IPCFormat(42)}))
+	assert.Error(t, io.WriteIPC(&buf, df, io.IPCOptions{Compression: io.IPCCompression(42)}))
+
+	_, err := io.ReadIPC(bytes.NewReader([]byte("not arrow data")))
+	assert.Error(t, err)
+}
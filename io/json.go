@@ -0,0 +1,704 @@
+package io
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// DefaultJSONBatchSize is the number of NDJSON rows read into each chunk when JSONOptions.BatchSize is not set.
+const DefaultJSONBatchSize = 1024
+
+// JSONOptions configures how JSON and NDJSON data is read.
+type JSONOptions struct {
+	// Schema is the schema of the data. Keys that are not in the schema are ignored,
+	// and values that cannot be converted to the type of their field are an error.
+	// Numbers and booleans read into string fields keep their JSON text, but are not converted into each other.
+	// If nil, the schema is inferred from the values, see ReadJSON.
+	Schema *arrow.Schema
+	// BatchSize is the number of rows read into each chunk of NDJSON data.
+	BatchSize int
+	// InferStringTypes parses the columns holding only strings like CSV columns when the schema is inferred,
+	// so that a column of numeric strings becomes a numeric column.
+	// By default, JSON strings are strings, numbers are int64 or float64, and booleans are bools.
+	InferStringTypes bool
+}
+
+// jsonObject is a decoded JSON object, which remembers the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// ReadJSON reads a JSON array of objects from an io.Reader and returns a DataFrame.
+// Unless a schema is given, every key becomes a column, in order of first appearance.
+// Scalar columns take the type of their JSON values: integers become int64 columns, other numbers float64,
+// and columns mixing several JSON types become strings. Nested objects become struct columns
+// and arrays become list columns. Columns holding only nulls are read as strings.
+func ReadJSON(input io.Reader, opts JSONOptions) (*dataframe.DataFrame, error) {
+	dec := newJSONDecoder(input)
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON array of objects, got %T", v)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON array")
+	}
+	rows := make([]*jsonObject, len(values))
+	for i, v := range values {
+		if rows[i], ok = v.(*jsonObject); !ok {
+			return nil, fmt.Errorf("row %d is not a JSON object", i)
+		}
+	}
+
+	schema := opts.Schema
+	if schema == nil {
+		schema, err = inferJSONSchema(rows, opts.InferStringTypes)
+		if err != nil {
+			return nil, err
+		}
+		schema = resolveJSONSchema(schema)
+	}
+	return buildJSONDataFrame(schema, rows, 0)
+}
+
+// ReadJSONFile reads a JSON array of objects from a path and returns a DataFrame.
+// Compressed files are decompressed, see CompressionFromPath.
+func ReadJSONFile(path string, opts JSONOptions) (*dataframe.DataFrame, error) {
+	fsys, name := localFile(path)
+	return ReadJSONFS(fsys, name, opts)
+}
+
+// ReadJSONFS reads the named file of a file system holding a JSON array of objects, and returns a DataFrame.
+// Compressed files are decompressed, see CompressionFromPath.
+func ReadJSONFS(fsys fs.FS, name string, opts JSONOptions) (*dataframe.DataFrame, error) {
+	file, err := openFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadJSON(file, opts)
+}
+
+// ReadNDJSON reads newline-delimited JSON objects from an io.Reader and returns a DataFrame.
+// Rows are read in batches of opts.BatchSize, and every batch becomes a chunk of the columns.
+// Unless a schema is given, it is inferred like in ReadJSON, see NDJSONReader.Next,
+// and the columns of keys that first appear in later batches are null in earlier ones.
+// The columns of earlier batches are cast to the types promoted by later ones.
+func ReadNDJSON(input io.Reader, opts JSONOptions) (*dataframe.DataFrame, error) {
+	r := NewNDJSONReader(input, opts)
+	var frames []*dataframe.DataFrame
+	defer func() {
+		for _, frame := range frames {
+			frame.Release()
+		}
+	}()
+	for {
+		df, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, df)
+	}
+	if len(frames) == 0 && r.Schema() != nil {
+		return buildJSONDataFrame(r.Schema(), nil, 0)
+	}
+	for i, frame := range frames {
+		promoted, err := castJSONDataFrame(frame, r.Schema())
+		if err != nil {
+			return nil, err
+		}
+		frame.Release()
+		frames[i] = promoted
+	}
+	return dataframe.Concat(frames, dataframe.ConcatDiagonal)
+}
+
+// ReadNDJSONFile reads newline-delimited JSON objects from a path and returns a DataFrame.
+// Compressed files are decompressed, see CompressionFromPath.
+func ReadNDJSONFile(path string, opts JSONOptions) (*dataframe.DataFrame, error) {
+	fsys, name := localFile(path)
+	return ReadNDJSONFS(fsys, name, opts)
+}
+
+// ReadNDJSONFS reads the named file of a file system holding newline-delimited JSON objects, and returns a DataFrame.
+// Compressed files are decompressed, see CompressionFromPath.
+func ReadNDJSONFS(fsys fs.FS, name string, opts JSONOptions) (*dataframe.DataFrame, error) {
+	file, err := openFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadNDJSON(file, opts)
+}
+
+// NDJSONReader reads newline-delimited JSON objects from a stream, one batch of rows at a time.
+type NDJSONReader struct {
+	dec       *json.Decoder
+	schema    *arrow.Schema
+	inferred  bool
+	inferStr  bool
+	batchSize int
+	row       int
+	// inferredSchema is the inferred schema, where the types of values that were all null are still arrow.Null.
+	inferredSchema *arrow.Schema
+}
+
+// NewNDJSONReader creates a new NDJSONReader reading from input.
+func NewNDJSONReader(input io.Reader, opts JSONOptions) *NDJSONReader {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultJSONBatchSize
+	}
+	return &NDJSONReader{
+		dec:       newJSONDecoder(input),
+		schema:    opts.Schema,
+		inferred:  opts.Schema == nil,
+		inferStr:  opts.InferStringTypes,
+		batchSize: batchSize,
+	}
+}
+
+// Schema returns the schema of the last batch.
+// Unless one was given, it is nil until the first batch is read.
+func (r *NDJSONReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads the next batch of rows into a DataFrame.
+// It returns io.EOF once every row has been read.
+// Unless a schema was given, the schema of the first batch is inferred from its rows,
+// and widened with the keys that first appear in later batches, which are added as new columns.
+// When the values of a column have another type in a later batch, the type of the column is promoted:
+// columns holding only nulls take the new type, int64 columns become float64 columns,
+// and columns mixing other types become strings. Batches that were already returned keep their types,
+// and a column that cannot be promoted, such as a struct column holding strings, is a *mangoerr.TypeMismatchError.
+func (r *NDJSONReader) Next() (*dataframe.DataFrame, error) {
+	rows := make([]*jsonObject, 0, r.batchSize)
+	for len(rows) < r.batchSize {
+		v, err := decodeJSONValue(r.dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", r.row, err)
+		}
+		row, ok := v.(*jsonObject)
+		if !ok {
+			return nil, fmt.Errorf("row %d is not a JSON object", r.row)
+		}
+		rows = append(rows, row)
+		r.row++
+	}
+	if len(rows) == 0 {
+		return nil, io.EOF
+	}
+
+	first := r.row - len(rows)
+	if r.inferred {
+		schema, err := inferJSONSchema(rows, r.inferStr)
+		if err == nil {
+			r.inferredSchema, err = promoteJSONSchema(r.inferredSchema, schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rows %d to %d: %w", first, r.row-1, err)
+		}
+		r.schema = resolveJSONSchema(r.inferredSchema)
+	}
+	return buildJSONDataFrame(r.schema, rows, first)
+}
+
+// promoteJSONSchema merges the schema inferred from a batch of rows into the schema of the previous batches, which may be nil.
+// The fields that are not in the previous schema are added at its end, and the types of the other fields are promoted,
+// see promoteJSONType.
+func promoteJSONSchema(prev, next *arrow.Schema) (*arrow.Schema, error) {
+	if prev == nil {
+		return next, nil
+	}
+	fields, err := promoteJSONFields(prev.Fields(), next.Fields())
+	if err != nil {
+		return nil, err
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func promoteJSONFields(prev, next []arrow.Field) ([]arrow.Field, error) {
+	fields := append([]arrow.Field{}, prev...)
+	for _, field := range next {
+		found := false
+		for i := range fields {
+			if fields[i].Name != field.Name {
+				continue
+			}
+			dtype, err := promoteJSONType(fields[i].Type, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name, err)
+			}
+			fields[i].Type = dtype
+			found = true
+			break
+		}
+		if !found {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// promoteJSONType returns the type of a column holding values of both inferred types:
+// values that were all null take the other type, int64 and float64 become float64,
+// and other scalar types become strings. Structs merge their fields, and lists promote their elements.
+func promoteJSONType(prev, next arrow.DataType) (arrow.DataType, error) {
+	switch {
+	case prev.ID() == arrow.NULL:
+		return next, nil
+	case next.ID() == arrow.NULL, arrow.TypeEqual(prev, next):
+		return prev, nil
+	case isJSONNumber(prev) && isJSONNumber(next):
+		return arrow.PrimitiveTypes.Float64, nil
+	case isJSONScalar(prev) && isJSONScalar(next):
+		return arrow.BinaryTypes.String, nil
+	case prev.ID() == arrow.STRUCT && next.ID() == arrow.STRUCT:
+		fields, err := promoteJSONFields(prev.(*arrow.StructType).Fields(), next.(*arrow.StructType).Fields())
+		if err != nil {
+			return nil, err
+		}
+		return arrow.StructOf(fields...), nil
+	case prev.ID() == arrow.LIST && next.ID() == arrow.LIST:
+		elem, err := promoteJSONType(prev.(*arrow.ListType).Elem(), next.(*arrow.ListType).Elem())
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elem), nil
+	}
+	return nil, &mangoerr.TypeMismatchError{Expected: prev.String(), Actual: next}
+}
+
+func isJSONNumber(dtype arrow.DataType) bool {
+	return dtype.ID() == arrow.INT64 || dtype.ID() == arrow.FLOAT64
+}
+
+func isJSONScalar(dtype arrow.DataType) bool {
+	return isJSONNumber(dtype) || dtype.ID() == arrow.BOOL || dtype.ID() == arrow.STRING
+}
+
+// castJSONDataFrame casts the columns of a batch read by an NDJSONReader to the types of the given schema,
+// which was promoted by later batches. Numbers and booleans cast to strings are written like in JSON.
+// The returned DataFrame holds its own references to the columns.
+func castJSONDataFrame(df *dataframe.DataFrame, schema *arrow.Schema) (*dataframe.DataFrame, error) {
+	cols := make([]series.Series, 0, df.Width())
+	for _, col := range df.Series {
+		idx := schema.FieldIndices(col.Name)
+		if idx == nil {
+			col.Retain()
+			cols = append(cols, col)
+			continue
+		}
+		casted, err := castJSONColumn(col, schema.Field(idx[0]).Type)
+		if err != nil {
+			for _, col := range cols {
+				col.Release()
+			}
+			return nil, err
+		}
+		cols = append(cols, casted)
+	}
+	return dataframe.NewDataFrame(cols), nil
+}
+
+func castJSONColumn(col series.Series, dtype arrow.DataType) (series.Series, error) {
+	if arrow.TypeEqual(col.DataType(), dtype) {
+		col.Retain()
+		return col, nil
+	}
+	b := array.NewBuilder(series.DefaultAllocator(), dtype)
+	defer b.Release()
+	for i := 0; i < col.Len(); i++ {
+		var v interface{}
+		if value := col.ValueExn(i); value.Valid {
+			v = jsonValue(value.Value)
+		}
+		if err := appendValue(b, stringifyJSONValue(v, dtype)); err != nil {
+			return series.Series{}, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+	}
+	arr := b.NewArray()
+	defer arr.Release()
+	return series.NewSeriesFromArray(col.Name, arr), nil
+}
+
+// jsonValue converts a value of a Series to a decoded JSON value, with structs as *jsonObject.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		obj := &jsonObject{values: make(map[string]interface{}, len(v))}
+		for key, value := range v {
+			obj.keys = append(obj.keys, key)
+			obj.values[key] = jsonValue(value)
+		}
+		return obj
+	case []interface{}:
+		elems := make([]interface{}, len(v))
+		for i, elem := range v {
+			elems[i] = jsonValue(elem)
+		}
+		return elems
+	}
+	return v
+}
+
+// stringifyJSONValue writes the numbers and booleans of v that are held by string fields of dtype like in JSON,
+// so that columns mixing several JSON types, or read with a string type, keep the text of their values.
+func stringifyJSONValue(v interface{}, dtype arrow.DataType) interface{} {
+	switch dtype := dtype.(type) {
+	case *arrow.StringType:
+		switch v := v.(type) {
+		case int64:
+			return strconv.FormatInt(v, 10)
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
+		}
+	case *arrow.StructType:
+		if obj, ok := v.(*jsonObject); ok {
+			values := make(map[string]interface{}, len(obj.values))
+			for _, field := range dtype.Fields() {
+				values[field.Name] = stringifyJSONValue(obj.values[field.Name], field.Type)
+			}
+			return &jsonObject{keys: obj.keys, values: values}
+		}
+	case *arrow.ListType:
+		if arr, ok := v.([]interface{}); ok {
+			elems := make([]interface{}, len(arr))
+			for i, elem := range arr {
+				elems[i] = stringifyJSONValue(elem, dtype.Elem())
+			}
+			return elems
+		}
+	}
+	return v
+}
+
+// WriteJSON writes a DataFrame to an io.Writer as a JSON array of objects, with one key per column.
+// Struct columns are written as objects and list columns as arrays.
+func WriteJSON(output io.Writer, df *dataframe.DataFrame) error {
+	if err := df.Validate(); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(output)
+	w.WriteByte('[')
+	for i := 0; i < df.Height(); i++ {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		if err := writeJSONRow(w, df, i); err != nil {
+			return err
+		}
+	}
+	w.WriteString("]\n")
+	return w.Flush()
+}
+
+// WriteJSONFile writes a DataFrame to a file as a JSON array of objects.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteJSONFile(df *dataframe.DataFrame, path string) error {
+	fsys, name := localFile(path)
+	return WriteJSONFS(fsys, df, name)
+}
+
+// WriteJSONFS writes a DataFrame to the named file of a file system as a JSON array of objects.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteJSONFS(fsys WriteFS, df *dataframe.DataFrame, name string) error {
+	return writeFile(fsys, name, func(w io.Writer) error {
+		return WriteJSON(w, df)
+	})
+}
+
+// WriteNDJSON writes a DataFrame to an io.Writer as newline-delimited JSON objects, with one key per column.
+func WriteNDJSON(output io.Writer, df *dataframe.DataFrame) error {
+	if err := df.Validate(); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(output)
+	for i := 0; i < df.Height(); i++ {
+		if err := writeJSONRow(w, df, i); err != nil {
+			return err
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+// WriteNDJSONFile writes a DataFrame to a file as newline-delimited JSON objects.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteNDJSONFile(df *dataframe.DataFrame, path string) error {
+	fsys, name := localFile(path)
+	return WriteNDJSONFS(fsys, df, name)
+}
+
+// WriteNDJSONFS writes a DataFrame to the named file of a file system as newline-delimited JSON objects.
+// The file is compressed according to its extension, see CompressionFromPath.
+func WriteNDJSONFS(fsys WriteFS, df *dataframe.DataFrame, name string) error {
+	return writeFile(fsys, name, func(w io.Writer) error {
+		return WriteNDJSON(w, df)
+	})
+}
+
+// writeJSONRow writes row i of the DataFrame as a JSON object, keeping the order of the columns.
+func writeJSONRow(w *bufio.Writer, df *dataframe.DataFrame, i int) error {
+	w.WriteByte('{')
+	for j, col := range df.Series {
+		if j > 0 {
+			w.WriteByte(',')
+		}
+		key, err := json.Marshal(col.Name)
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if v := col.ValueExn(i); v.Valid {
+			value = v.Value
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("column %s, row %d: %w", col.Name, i, err)
+		}
+		w.Write(key)
+		w.WriteByte(':')
+		w.Write(encoded)
+	}
+	return w.WriteByte('}')
+}
+
+func newJSONDecoder(input io.Reader) *json.Decoder {
+	dec := json.NewDecoder(input)
+	dec.UseNumber()
+	return dec
+}
+
+// decodeJSONValue decodes the next JSON value from dec.
+// Objects are decoded as *jsonObject, arrays as []interface{}, and numbers as int64 when they fit, or float64.
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			obj := &jsonObject{values: make(map[string]interface{})}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyTok.(string)
+				v, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := obj.values[key]; !ok {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = v
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			arr := []interface{}{}
+			for dec.More() {
+				v, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %s", tok)
+	case json.Number:
+		if i, err := tok.Int64(); err == nil {
+			return i, nil
+		}
+		return tok.Float64()
+	}
+	return tok, nil
+}
+
+// inferJSONSchema infers the schema of the given rows, see inferJSONType.
+// The types of values that are all null are arrow.Null, until resolveJSONSchema makes them strings.
+func inferJSONSchema(rows []*jsonObject, inferStrings bool) (*arrow.Schema, error) {
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = row
+	}
+	dtype, err := inferJSONType(values, inferStrings)
+	if err != nil {
+		return nil, err
+	}
+	return arrow.NewSchema(dtype.(*arrow.StructType).Fields(), nil), nil
+}
+
+// resolveJSONSchema replaces the arrow.Null types of an inferred schema, which only held nulls, with strings.
+func resolveJSONSchema(schema *arrow.Schema) *arrow.Schema {
+	fields := append([]arrow.Field{}, schema.Fields()...)
+	for i := range fields {
+		fields[i].Type = resolveJSONType(fields[i].Type)
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+func resolveJSONType(dtype arrow.DataType) arrow.DataType {
+	switch dtype := dtype.(type) {
+	case *arrow.NullType:
+		return arrow.BinaryTypes.String
+	case *arrow.StructType:
+		fields := append([]arrow.Field{}, dtype.Fields()...)
+		for i := range fields {
+			fields[i].Type = resolveJSONType(fields[i].Type)
+		}
+		return arrow.StructOf(fields...)
+	case *arrow.ListType:
+		return arrow.ListOf(resolveJSONType(dtype.Elem()))
+	}
+	return dtype
+}
+
+// inferJSONType infers the arrow datatype of the given decoded JSON values.
+// When inferStrings is true, the type of values that are all strings is inferred from their contents.
+func inferJSONType(values []interface{}, inferStrings bool) (arrow.DataType, error) {
+	var objects []*jsonObject
+	var arrays [][]interface{}
+	var scalars []interface{}
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			scalars = append(scalars, primitive.Null{})
+		case *jsonObject:
+			objects = append(objects, v)
+		case []interface{}:
+			arrays = append(arrays, v)
+		default:
+			scalars = append(scalars, v)
+		}
+	}
+
+	switch {
+	case objects != nil && arrays != nil:
+		return nil, errors.New("cannot mix objects and arrays")
+	case objects != nil:
+		var keys []string
+		fieldValues := make(map[string][]interface{})
+		for _, obj := range objects {
+			for _, key := range obj.keys {
+				if _, ok := fieldValues[key]; !ok {
+					keys = append(keys, key)
+				}
+				fieldValues[key] = append(fieldValues[key], obj.values[key])
+			}
+		}
+		fields := make([]arrow.Field, len(keys))
+		for i, key := range keys {
+			dtype, err := inferJSONType(fieldValues[key], inferStrings)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			fields[i] = arrow.Field{Name: key, Type: dtype, Nullable: true}
+		}
+		return arrow.StructOf(fields...), nil
+	case arrays != nil:
+		var elems []interface{}
+		for _, arr := range arrays {
+			elems = append(elems, arr...)
+		}
+		dtype, err := inferJSONType(elems, inferStrings)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(dtype), nil
+	}
+
+	return inferJSONScalarType(scalars, inferStrings)
+}
+
+// inferJSONScalarType returns the arrow datatype of JSON strings, numbers and booleans.
+func inferJSONScalarType(scalars []interface{}, inferStrings bool) (arrow.DataType, error) {
+	var ints, floats, bools, strs int
+	for _, v := range scalars {
+		switch v.(type) {
+		case int64:
+			ints++
+		case float64:
+			floats++
+		case bool:
+			bools++
+		case string:
+			strs++
+		}
+	}
+	switch {
+	case ints+floats+bools+strs == 0:
+		return arrow.Null, nil
+	case floats+bools+strs == 0:
+		return arrow.PrimitiveTypes.Int64, nil
+	case bools+strs == 0:
+		return arrow.PrimitiveTypes.Float64, nil
+	case ints+floats+strs == 0:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case ints+floats+bools == 0 && inferStrings:
+		return primitive.InferDatatype(scalars)
+	}
+	return arrow.BinaryTypes.String, nil
+}
+
+// buildJSONDataFrame builds a DataFrame with the given schema from decoded JSON rows.
+// first is the number of the first row, reported in errors.
+func buildJSONDataFrame(schema *arrow.Schema, rows []*jsonObject, first int) (*dataframe.DataFrame, error) {
+	mem := series.DefaultAllocator()
+	cols := make([]series.Series, 0, len(schema.Fields()))
+	release := func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}
+	for _, field := range schema.Fields() {
+		b := array.NewBuilder(mem, field.Type)
+		defer b.Release()
+		for j, row := range rows {
+			if err := appendValue(b, stringifyJSONValue(row.values[field.Name], field.Type)); err != nil {
+				release()
+				return nil, fmt.Errorf("column %s, row %d: %w", field.Name, first+j, err)
+			}
+		}
+		arr := b.NewArray()
+		cols = append(cols, series.NewSeriesFromArray(field.Name, arr))
+		arr.Release()
+	}
+	df, err := dataframe.NewDataFrameChecked(cols)
+	if err != nil {
+		release()
+	}
+	return df, err
+}
@@ -0,0 +1,257 @@
+package io_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kstremick/mango/core/mangoerr"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/internal/memtest"
+	"github.com/kstremick/mango/io"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/zeebo/assert"
+)
+
+func columnValues(s series.Series) []interface{} {
+	ret := make([]interface{}, s.Len())
+	for i := range ret {
+		if v := s.ValueExn(i); v.Valid {
+			ret[i] = v.Value
+		}
+	}
+	return ret
+}
+
+func TestReadJSON(t *testing.T) {
+	data := `[
+		{"id": 1, "name": "a", "score": 1.5, "ok": true, "user": {"name": "x", "age": 30}, "tags": ["p", "q"]},
+		{"id": 2, "score": 2, "ok": null, "user": null, "tags": []},
+		{"id": 3, "name": "c", "score": null, "ok": false, "user": {"name": "z"}, "tags": null, "extra": null}
+	]`
+	df, err := io.ReadJSON(strings.NewReader(data), io.JSONOptions{})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"id", "name", "score", "ok", "user", "tags", "extra"}, df.GetColumnNames())
+
+	type testCase struct {
+		column   string
+		dtype    arrow.DataType
+		expected []interface{}
+	}
+	user := arrow.StructOf(
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		arrow.Field{Name: "age", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	)
+	testCases := []testCase{
+		{"id", arrow.PrimitiveTypes.Int64, []interface{}{int64(1), int64(2), int64(3)}},
+		{"name", arrow.BinaryTypes.String, []interface{}{"a", nil, "c"}},
+		{"score", arrow.PrimitiveTypes.Float64, []interface{}{1.5, 2.0, nil}},
+		{"ok", arrow.FixedWidthTypes.Boolean, []interface{}{true, nil, false}},
+		{"user", user, []interface{}{
+			map[string]interface{}{"name": "x", "age": int64(30)},
+			nil,
+			map[string]interface{}{"name": "z", "age": nil},
+		}},
+		{"tags", arrow.ListOf(arrow.BinaryTypes.String), []interface{}{
+			[]interface{}{"p", "q"},
+			[]interface{}{},
+			nil,
+		}},
+		{"extra", arrow.BinaryTypes.String, []interface{}{nil, nil, nil}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			col, err := df.Column(tc.column)
+			assert.NoError(t, err)
+			assert.True(t, arrow.TypeEqual(tc.dtype, col.DataType()))
+			assert.DeepEqual(t, tc.expected, columnValues(col))
+		})
+	}
+}
+
+func TestReadJSONSchema(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "id", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	df, err := io.ReadJSON(strings.NewReader(`[{"id": 1, "score": 2, "ignored": true}, {"id": 2}]`), io.JSONOptions{Schema: schema})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"score", "id"}, df.GetColumnNames())
+	assert.DeepEqual(t, []interface{}{2.0, nil}, columnValues(df.Series[0]))
+	assert.DeepEqual(t, []interface{}{"1", "2"}, columnValues(df.Series[1]))
+
+	_, err = io.ReadJSON(strings.NewReader(`[{"score": "high"}]`), io.JSONOptions{Schema: schema})
+	assert.Error(t, err)
+}
+
+func TestReadJSONStrings(t *testing.T) {
+	data := `[{"zip": "01234", "flag": "1", "mixed": 1}, {"zip": "98765", "flag": "0", "mixed": "a"}]`
+	df, err := io.ReadJSON(strings.NewReader(data), io.JSONOptions{})
+	assert.NoError(t, err)
+	for _, col := range df.Series {
+		assert.Equal(t, arrow.STRING, col.Type())
+	}
+	assert.DeepEqual(t, []interface{}{"01234", "98765"}, columnValues(df.Series[0]))
+	assert.DeepEqual(t, []interface{}{"1", "a"}, columnValues(df.Series[2]))
+
+	df, err = io.ReadJSON(strings.NewReader(data), io.JSONOptions{InferStringTypes: true})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(1234), int64(98765)}, columnValues(df.Series[0]))
+	assert.Equal(t, arrow.STRING, df.Series[2].Type())
+}
+
+func TestReadJSONInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"id": 1}`,
+		`[1, 2]`,
+		`[{"id": 1}] [{"id": 2}]`,
+		`[{"id": {"a": 1}}, {"id": [1]}]`,
+		`[{"id": 1}`,
+	} {
+		_, err := io.ReadJSON(strings.NewReader(data), io.JSONOptions{})
+		assert.Error(t, err)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	data := `{"event": "click", "ts": 1, "meta": {"x": 1}}
+{"event": "view", "ts": 2, "meta": {"x": 2}}
+
+{"event": "click", "ts": 3, "meta": null}
+`
+	df, err := io.ReadNDJSON(strings.NewReader(data), io.JSONOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"event", "ts", "meta"}, df.GetColumnNames())
+	assert.DeepEqual(t, []int{2, 1}, df.Series[0].ChunkLengths())
+	assert.DeepEqual(t, []interface{}{"click", "view", "click"}, columnValues(df.Series[0]))
+	assert.DeepEqual(t, []interface{}{int64(1), int64(2), int64(3)}, columnValues(df.Series[1]))
+
+	r := io.NewNDJSONReader(strings.NewReader(data), io.JSONOptions{BatchSize: 2})
+	batch, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, batch.Height())
+	batch, err = r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batch.Height())
+	_, err = r.Next()
+	assert.Equal(t, "EOF", err.Error())
+
+	late, err := io.ReadNDJSON(strings.NewReader("{\"a\": 1}\n{\"a\": 2, \"b\": \"late\"}\n"), io.JSONOptions{BatchSize: 1})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"a", "b"}, late.GetColumnNames())
+	assert.DeepEqual(t, []interface{}{nil, "late"}, columnValues(late.Series[1]))
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ts", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	empty, err := io.ReadNDJSON(strings.NewReader(""), io.JSONOptions{Schema: schema})
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []string{"ts"}, empty.GetColumnNames())
+	assert.Equal(t, 0, empty.Height())
+}
+
+func TestReadNDJSONPromotion(t *testing.T) {
+	type testCase struct {
+		name     string
+		data     string
+		dtype    arrow.DataType
+		expected []interface{}
+	}
+	testCases := []testCase{
+		{"int then bool", "{\"a\": 2}\n{\"a\": true}\n", arrow.BinaryTypes.String, []interface{}{"2", "true"}},
+		{"bool then int", "{\"a\": true}\n{\"a\": 1}\n", arrow.BinaryTypes.String, []interface{}{"true", "1"}},
+		{"null then int", "{\"a\": null}\n{\"a\": 3}\n", arrow.PrimitiveTypes.Int64, []interface{}{nil, int64(3)}},
+		{"int then null", "{\"a\": 3}\n{}\n", arrow.PrimitiveTypes.Int64, []interface{}{int64(3), nil}},
+		{"int then float", "{\"a\": 1}\n{\"a\": 2.5}\n", arrow.PrimitiveTypes.Float64, []interface{}{1.0, 2.5}},
+		{"int then string", "{\"a\": 1}\n{\"a\": \"late\"}\n", arrow.BinaryTypes.String, []interface{}{"1", "late"}},
+		{"int then float then string", "{\"a\": 1}\n{\"a\": 2.5}\n{\"a\": \"x\"}\n", arrow.BinaryTypes.String, []interface{}{"1", "2.5", "x"}},
+		{"struct of nulls then ints", "{\"a\": {\"x\": null}}\n{\"a\": {\"x\": 3, \"y\": true}}\n",
+			arrow.StructOf(arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int64, Nullable: true}, arrow.Field{Name: "y", Type: arrow.FixedWidthTypes.Boolean, Nullable: true}),
+			[]interface{}{map[string]interface{}{"x": nil, "y": nil}, map[string]interface{}{"x": int64(3), "y": true}}},
+		{"list of ints then floats", "{\"a\": [1, null]}\n{\"a\": [2.5]}\n", arrow.ListOf(arrow.PrimitiveTypes.Float64),
+			[]interface{}{[]interface{}{1.0, nil}, []interface{}{2.5}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			df, err := io.ReadNDJSON(strings.NewReader(tc.data), io.JSONOptions{BatchSize: 1})
+			assert.NoError(t, err)
+			assert.True(t, arrow.TypeEqual(tc.dtype, df.Series[0].DataType()))
+			assert.DeepEqual(t, tc.expected, columnValues(df.Series[0]))
+		})
+	}
+
+	r := io.NewNDJSONReader(strings.NewReader("{\"a\": null}\n{\"a\": 3}\n"), io.JSONOptions{BatchSize: 1})
+	_, err := r.Next()
+	assert.NoError(t, err)
+	batch, err := r.Next()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []interface{}{int64(3)}, columnValues(batch.Series[0]))
+
+	_, err = io.ReadNDJSON(strings.NewReader("{\"a\": {\"x\": 1}}\n{\"a\": \"x\"}\n"), io.JSONOptions{BatchSize: 1})
+	assert.True(t, errors.Is(err, mangoerr.ErrTypeMismatch))
+}
+
+func TestReadNDJSONRowErrors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	type testCase struct {
+		name string
+		data string
+	}
+	testCases := []testCase{
+		{"bool to int", "{\"a\": 1}\n{\"a\": 2}\n{\"a\": true}\n"},
+		{"float to int", "{\"a\": 1}\n{\"a\": 2}\n{\"a\": 2.5}\n"},
+		{"string to int", "{\"a\": 1}\n{\"a\": 2}\n{\"a\": \"x\"}\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := io.ReadNDJSON(strings.NewReader(tc.data), io.JSONOptions{Schema: schema, BatchSize: 1})
+			assert.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), "row 2"))
+		})
+	}
+
+	bools := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.FixedWidthTypes.Boolean, Nullable: true}}, nil)
+	_, err := io.ReadNDJSON(strings.NewReader("{\"a\": 1}\n"), io.JSONOptions{Schema: bools})
+	assert.Error(t, err)
+}
+
+func TestReadNDJSONRelease(t *testing.T) {
+	memtest.CheckedAllocator(t)
+	for _, data := range []string{
+		"{\"a\": 1}\n{\"a\": 2, \"b\": \"x\"}\n{\"a\": 3}\n",
+		"{\"a\": 1, \"b\": null}\n{\"a\": 2.5, \"b\": true}\n{\"a\": \"x\"}\n",
+		"{\"a\": {\"x\": null}, \"b\": [1]}\n{\"a\": {\"x\": 1}, \"b\": [2.5]}\n{\"a\": null}\n",
+	} {
+		df, err := io.ReadNDJSON(strings.NewReader(data), io.JSONOptions{BatchSize: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, df.Height())
+		df.Release()
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data := `[{"id":1,"name":"a","user":{"age":30,"name":"x"},"tags":["p",null]},{"id":2,"name":null,"user":null,"tags":[]}]` + "\n"
+	df, err := io.ReadJSON(strings.NewReader(data), io.JSONOptions{})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, io.WriteJSON(&buf, df))
+	assert.Equal(t, data, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, io.WriteNDJSON(&buf, df))
+	assert.Equal(t, `{"id":1,"name":"a","user":{"age":30,"name":"x"},"tags":["p",null]}
+{"id":2,"name":null,"user":null,"tags":[]}
+`, buf.String())
+
+	dir := t.TempDir()
+	assert.NoError(t, io.WriteJSONFile(df, filepath.Join(dir, "data.json")))
+	assert.NoError(t, io.WriteNDJSONFile(df, filepath.Join(dir, "data.ndjson")))
+	fromJSON, err := io.ReadJSONFile(filepath.Join(dir, "data.json"), io.JSONOptions{})
+	assert.NoError(t, err)
+	fromNDJSON, err := io.ReadNDJSONFile(filepath.Join(dir, "data.ndjson"), io.JSONOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, df.String(), fromJSON.String())
+	assert.Equal(t, df.String(), fromNDJSON.String())
+}
@@ -0,0 +1,75 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// MemFS is an in-memory WriteFS, mostly useful for tests.
+// It is safe for concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+// NewMemFS creates a new empty MemFS.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(fstest.MapFS)}
+}
+
+// Open opens the named file or directory.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+// ReadDir reads the named directory.
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.ReadDir(name)
+}
+
+// Create creates or truncates the named file.
+// The file is only visible once the returned writer is closed.
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	return &memFile{fs: m, name: name}, nil
+}
+
+// MkdirAll creates the named directory, along with any missing parents.
+func (m *MemFS) MkdirAll(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		m.files[name] = &fstest.MapFile{Mode: fs.ModeDir | 0o755, ModTime: time.Now()}
+	}
+	return nil
+}
+
+// memFile buffers the data written to a file of a MemFS until it is closed.
+type memFile struct {
+	bytes.Buffer
+	fs   *MemFS
+	name string
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	f.fs.files[f.name] = &fstest.MapFile{Data: f.Bytes(), Mode: 0o644, ModTime: time.Now()}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package io
+
+import (
+	"os"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+)
+
+// mapFile reads the file at path in memory, since memory maps are only used on Unix systems.
+func mapFile(path string) (*memory.Buffer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewBufferBytes(data), nil
+}
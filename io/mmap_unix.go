@@ -0,0 +1,55 @@
+//go:build unix
+
+package io
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+)
+
+// mapFile maps the file at path in memory, read-only.
+// The mapping is removed once the returned buffer, and every buffer sliced from it, is released.
+func mapFile(path string) (*memory.Buffer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return memory.NewBufferBytes(nil), nil
+	}
+	if int64(int(size)) != size {
+		return nil, &fs.PathError{Op: "mmap", Path: path, Err: fmt.Errorf("file of %d bytes is too large", size)}
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &fs.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	buf := memory.NewResizableBuffer(unmapper{})
+	buf.Reset(data)
+	return buf, nil
+}
+
+// unmapper is the allocator of the buffers returned by mapFile, which only frees them.
+type unmapper struct{}
+
+func (unmapper) Allocate(int) []byte {
+	panic("io: cannot allocate mapped memory")
+}
+
+func (unmapper) Reallocate(int, []byte) []byte {
+	panic("io: cannot reallocate mapped memory")
+}
+
+func (unmapper) Free(b []byte) {
+	syscall.Munmap(b)
+}
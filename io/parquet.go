@@ -1,53 +1,102 @@
 package io
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/apache/arrow/go/v12/parquet"
 	"github.com/apache/arrow/go/v12/parquet/file"
 	"github.com/apache/arrow/go/v12/parquet/pqarrow"
 	"github.com/kstremick/mango/core/dataframe"
 	"github.com/kstremick/mango/core/series"
 )
 
-// BOUNDED_LEN is the maximum number of rows to read from a parquet file
-// TODO: make this configurable, or have smarter defaults by looking into the file metadata first
+// BOUNDED_LEN is the maximum number of rows to read from a parquet file.
+//
+// Deprecated: parquet files are read whole, and BOUNDED_LEN is no longer used.
 const BOUNDED_LEN = 1_000_000_000
 
-// ReadParquetFile reads a parquet file and returns a DataFrame
+// parquetRowGroupSize is the maximum number of rows in each row group of written parquet files.
+const parquetRowGroupSize = 1024 * 1024
+
+// ReadParquet reads parquet data and returns a DataFrame
 // using the arrow parquet reader
-func ReadParquetFile(path string) (*dataframe.DataFrame, error) {
-	rdr, err := file.OpenParquetFile(path, true)
+func ReadParquet(input parquet.ReaderAtSeeker) (*dataframe.DataFrame, error) {
+	rdr, err := file.NewParquetReader(input)
 	if err != nil {
 		return nil, err
 	}
 	defer rdr.Close()
+	return readParquet(rdr)
+}
 
-	metadata := rdr.MetaData()
-	numColumns := metadata.Schema.NumColumns()
-	columns := make([]series.Series, numColumns)
-	for i := 0; i < numColumns; i++ {
-		ser, err := readParquetColumn(*rdr, i)
+// ReadParquetFile reads a parquet file and returns a DataFrame
+// using the arrow parquet reader
+func ReadParquetFile(path string) (*dataframe.DataFrame, error) {
+	fsys, name := localFile(path)
+	return ReadParquetFS(fsys, name)
+}
+
+// ReadParquetFS reads the named parquet file of a file system and returns a DataFrame
+// using the arrow parquet reader.
+// Files that do not support random access are read into memory first.
+func ReadParquetFS(fsys fs.FS, name string) (*dataframe.DataFrame, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	input, ok := f.(parquet.ReaderAtSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
 		if err != nil {
 			return nil, err
 		}
-		columns[i] = *ser
+		input = bytes.NewReader(data)
 	}
-
-	return dataframe.NewDataFrame(columns), nil
+	return ReadParquet(input)
 }
 
-// Reads the column at colIdx from the parquet file
-func readParquetColumn(rdr file.Reader, colIdx int) (*series.Series, error) {
-	newColSchema := rdr.MetaData().Schema.Column(colIdx)
-	colReader := pqarrow.ColumnReader{}
-	chunked, err := colReader.BuildArray(BOUNDED_LEN)
+// readParquet reads every column of the parquet file. Every row group becomes a chunk of the columns.
+func readParquet(rdr *file.Reader) (*dataframe.DataFrame, error) {
+	arrowRdr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, series.DefaultAllocator())
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := arrowRdr.ReadTable(context.Background())
 	if err != nil {
 		return nil, err
 	}
-	ret := series.NewSeriesFromChunked(newColSchema.Name(), chunked)
-	return &ret, nil
+	defer tbl.Release()
+	return dataframe.FromArrowTable(tbl)
+}
+
+// WriteParquet writes a DataFrame as parquet data to an io.Writer
+// using the arrow parquet writer
+func WriteParquet(output io.Writer, df *dataframe.DataFrame) error {
+	tbl, err := df.ToArrowTable()
+	if err != nil {
+		return err
+	}
+	defer tbl.Release()
+	// The parquet writer closes outputs that are io.Closers, which is left to the caller.
+	output = struct{ io.Writer }{output}
+	return pqarrow.WriteTable(tbl, output, parquetRowGroupSize, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
 }
 
 // WriteParquetFile writes a DataFrame to a parquet file
 // using the arrow parquet writer
 func WriteParquetFile(df *dataframe.DataFrame, path string) error {
-	panic("not implemented")
+	fsys, name := localFile(path)
+	return WriteParquetFS(fsys, df, name)
+}
+
+// WriteParquetFS writes a DataFrame to the named parquet file of a file system
+// using the arrow parquet writer
+func WriteParquetFS(fsys WriteFS, df *dataframe.DataFrame, name string) error {
+	return writeFile(fsys, name, func(w io.Writer) error {
+		return WriteParquet(w, df)
+	})
 }
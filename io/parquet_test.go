@@ -1,12 +1,21 @@
 package io_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/kstremick/mango/io"
+
+	"github.com/zeebo/assert"
 )
 
 func TestCsvToParquet(t *testing.T) {
-	df, _ := io.ReadCsvFile("testdata/titanic.csv")
-	io.WriteParquetFile(df, "testdata/titanic.parquet")
+	df, err := io.ReadCsvFile("testdata/titanic.csv")
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "titanic.parquet")
+	assert.NoError(t, io.WriteParquetFile(df, path))
+
+	out, err := io.ReadParquetFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, df.String(), out.String())
 }
@@ -0,0 +1,436 @@
+package io
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+)
+
+// sqlReadBatchSize is the number of rows read into each chunk by ReadSQL.
+const sqlReadBatchSize = 64 * 1024
+
+// DefaultSQLBatchSize is the number of rows inserted by each statement of WriteSQL when SQLWriteOptions.BatchSize is not set.
+const DefaultSQLBatchSize = 500
+
+// ReadSQL runs a query and returns its result as a DataFrame.
+// The types of the columns are found from their database types: integers become int64,
+// floating point and decimal numbers float64, booleans bool, dates date32, timestamps timestamps in microseconds,
+// and text string. The types of other columns are found from all of their values, see sqlValueType.
+// Rows are read in batches, and every batch becomes a chunk of the columns. Columns of known types are built
+// as their batches fill, while the values of the other columns are kept until all rows are read.
+func ReadSQL(ctx context.Context, db *sql.DB, query string, args ...any) (*dataframe.DataFrame, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	mem := series.DefaultAllocator()
+	builders := make([]array.Builder, len(colTypes))
+	// untyped holds the values of the columns of unknown types, whose builders are only created at the end.
+	untyped := make([][]any, len(colTypes))
+	chunks := make([][]arrow.Array, len(colTypes))
+	defer func() {
+		for i := range chunks {
+			for _, chunk := range chunks[i] {
+				chunk.Release()
+			}
+			if builders[i] != nil {
+				builders[i].Release()
+			}
+		}
+	}()
+	for i, ct := range colTypes {
+		if dtype := sqlColumnType(ct); dtype != nil {
+			builders[i] = array.NewBuilder(mem, dtype)
+		}
+	}
+	flush := func() {
+		for i, b := range builders {
+			if b != nil {
+				chunks[i] = append(chunks[i], b.NewArray())
+			}
+		}
+	}
+
+	dest := make([]any, len(colTypes))
+	scanned := make([]any, len(colTypes))
+	for i := range dest {
+		dest[i] = &scanned[i]
+	}
+	n := 0
+	for ; rows.Next(); n++ {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range scanned {
+			if raw, ok := v.([]byte); ok {
+				// Scanned bytes are only valid until the next row.
+				v = string(raw)
+			}
+			if builders[i] == nil {
+				untyped[i] = append(untyped[i], v)
+				continue
+			}
+			if err := appendSQLValue(builders[i], v); err != nil {
+				return nil, fmt.Errorf("column %s, row %d: %w", colTypes[i].Name(), n, err)
+			}
+		}
+		if (n+1)%sqlReadBatchSize == 0 {
+			flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if n%sqlReadBatchSize != 0 || n == 0 {
+		flush()
+	}
+
+	cols := make([]series.Series, 0, len(colTypes))
+	for i, ct := range colTypes {
+		if builders[i] == nil {
+			builders[i] = array.NewBuilder(mem, sqlValueType(untyped[i]))
+			for start := 0; start < n || start == 0; start += sqlReadBatchSize {
+				end := start + sqlReadBatchSize
+				if end > n {
+					end = n
+				}
+				for k, v := range untyped[i][start:end] {
+					if err := appendSQLValue(builders[i], v); err != nil {
+						return nil, fmt.Errorf("column %s, row %d: %w", ct.Name(), start+k, err)
+					}
+				}
+				chunks[i] = append(chunks[i], builders[i].NewArray())
+			}
+		}
+		cols = append(cols, series.NewSeriesFromChunked(ct.Name(), arrow.NewChunked(builders[i].Type(), chunks[i])))
+	}
+	df, err := dataframe.NewDataFrameChecked(cols)
+	if err != nil {
+		for i := range cols {
+			cols[i].Release()
+		}
+		return nil, err
+	}
+	return df, nil
+}
+
+// sqlTypes maps database type names to arrow datatypes.
+var sqlTypes = map[string]arrow.DataType{
+	"INTEGER": arrow.PrimitiveTypes.Int64, "INT": arrow.PrimitiveTypes.Int64, "BIGINT": arrow.PrimitiveTypes.Int64,
+	"SMALLINT": arrow.PrimitiveTypes.Int64, "TINYINT": arrow.PrimitiveTypes.Int64, "MEDIUMINT": arrow.PrimitiveTypes.Int64,
+	"INT2": arrow.PrimitiveTypes.Int64, "INT4": arrow.PrimitiveTypes.Int64, "INT8": arrow.PrimitiveTypes.Int64,
+	"SERIAL": arrow.PrimitiveTypes.Int64, "BIGSERIAL": arrow.PrimitiveTypes.Int64,
+
+	"REAL": arrow.PrimitiveTypes.Float64, "FLOAT": arrow.PrimitiveTypes.Float64, "DOUBLE": arrow.PrimitiveTypes.Float64,
+	"DOUBLE PRECISION": arrow.PrimitiveTypes.Float64, "FLOAT4": arrow.PrimitiveTypes.Float64, "FLOAT8": arrow.PrimitiveTypes.Float64,
+	"NUMERIC": arrow.PrimitiveTypes.Float64, "DECIMAL": arrow.PrimitiveTypes.Float64,
+
+	"BOOLEAN": arrow.FixedWidthTypes.Boolean, "BOOL": arrow.FixedWidthTypes.Boolean,
+
+	"TIMESTAMP": arrow.FixedWidthTypes.Timestamp_us, "TIMESTAMPTZ": arrow.FixedWidthTypes.Timestamp_us,
+	"DATETIME": arrow.FixedWidthTypes.Timestamp_us, "DATE": arrow.FixedWidthTypes.Date32,
+
+	"TEXT": arrow.BinaryTypes.String, "VARCHAR": arrow.BinaryTypes.String, "CHAR": arrow.BinaryTypes.String,
+	"CHARACTER VARYING": arrow.BinaryTypes.String, "CHARACTER": arrow.BinaryTypes.String, "BPCHAR": arrow.BinaryTypes.String,
+	"NVARCHAR": arrow.BinaryTypes.String, "CLOB": arrow.BinaryTypes.String, "UUID": arrow.BinaryTypes.String,
+	"JSON": arrow.BinaryTypes.String, "JSONB": arrow.BinaryTypes.String, "STRING": arrow.BinaryTypes.String,
+}
+
+// sqlColumnType returns the arrow datatype of a column, or nil if it cannot be found from its type.
+func sqlColumnType(ct *sql.ColumnType) arrow.DataType {
+	name := strings.ToUpper(ct.DatabaseTypeName())
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), "UNSIGNED"))
+	if dtype, ok := sqlTypes[name]; ok {
+		return dtype
+	}
+
+	if ct.ScanType() == nil {
+		return nil
+	}
+	switch scanType := ct.ScanType(); {
+	case scanType == reflect.TypeOf(time.Time{}) || scanType == reflect.TypeOf(sql.NullTime{}):
+		return arrow.FixedWidthTypes.Timestamp_us
+	case scanType == reflect.TypeOf(sql.NullInt64{}) || scanType == reflect.TypeOf(sql.NullInt32{}) || scanType == reflect.TypeOf(sql.NullInt16{}):
+		return arrow.PrimitiveTypes.Int64
+	case scanType == reflect.TypeOf(sql.NullFloat64{}):
+		return arrow.PrimitiveTypes.Float64
+	case scanType == reflect.TypeOf(sql.NullBool{}):
+		return arrow.FixedWidthTypes.Boolean
+	case scanType == reflect.TypeOf(sql.NullString{}):
+		return arrow.BinaryTypes.String
+	}
+	switch ct.ScanType().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return arrow.PrimitiveTypes.Int64
+	case reflect.Float32, reflect.Float64:
+		return arrow.PrimitiveTypes.Float64
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean
+	case reflect.String:
+		return arrow.BinaryTypes.String
+	}
+	return nil
+}
+
+// sqlValueType returns the arrow datatype of a column from its scanned values: int64, bool or timestamp
+// if all of its non-null values have that type, float64 if they are all numbers, and string otherwise.
+func sqlValueType(values []any) arrow.DataType {
+	var dtype arrow.DataType
+	for _, v := range values {
+		var vtype arrow.DataType
+		switch v.(type) {
+		case nil:
+			continue
+		case int64:
+			vtype = arrow.PrimitiveTypes.Int64
+		case float64:
+			vtype = arrow.PrimitiveTypes.Float64
+		case bool:
+			vtype = arrow.FixedWidthTypes.Boolean
+		case time.Time:
+			vtype = arrow.FixedWidthTypes.Timestamp_us
+		default:
+			return arrow.BinaryTypes.String
+		}
+		switch {
+		case dtype == nil || arrow.TypeEqual(dtype, vtype):
+			dtype = vtype
+		case isSQLNumber(dtype) && isSQLNumber(vtype):
+			dtype = arrow.PrimitiveTypes.Float64
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+	if dtype == nil {
+		return arrow.BinaryTypes.String
+	}
+	return dtype
+}
+
+func isSQLNumber(dtype arrow.DataType) bool {
+	return dtype.ID() == arrow.INT64 || dtype.ID() == arrow.FLOAT64
+}
+
+// appendSQLValue appends a value scanned from a database to b, converting it to the type of the builder.
+// Besides the conversions of appendValue, times and strings are converted to dates and timestamps,
+// and times, numbers and booleans to strings, since columns mixing several types are read as strings.
+func appendSQLValue(b array.Builder, v any) error {
+	if v == nil {
+		return appendValue(b, v)
+	}
+	switch b := b.(type) {
+	case *array.TimestampBuilder:
+		unit := b.Type().(*arrow.TimestampType).Unit
+		switch v := v.(type) {
+		case time.Time:
+			b.Append(arrow.Timestamp(v.UnixNano() / int64(unit.Multiplier())))
+			return nil
+		case string:
+			ts, err := arrow.TimestampFromString(v, unit)
+			if err != nil {
+				return err
+			}
+			b.Append(ts)
+			return nil
+		}
+	case *array.Date32Builder:
+		switch v := v.(type) {
+		case time.Time:
+			b.Append(arrow.Date32FromTime(v))
+			return nil
+		case string:
+			t, err := time.Parse("2006-01-02", strings.TrimSpace(v))
+			if err != nil {
+				return err
+			}
+			b.Append(arrow.Date32FromTime(t))
+			return nil
+		}
+	case *array.StringBuilder:
+		if t, ok := v.(time.Time); ok {
+			b.Append(t.Format(time.RFC3339Nano))
+			return nil
+		}
+		if str, ok := primitive.AttemptConversionT[string](v); ok {
+			b.Append(str)
+			return nil
+		}
+	default:
+		return appendValue(b, v)
+	}
+	return fmt.Errorf("cannot convert %v to %s", v, b.Type())
+}
+
+// SQLWriteMode is the way WriteSQL treats the table it writes to.
+type SQLWriteMode int
+
+const (
+	// SQLCreate creates the table, and fails if it already exists.
+	SQLCreate SQLWriteMode = iota
+	// SQLAppend inserts the rows into the existing table.
+	SQLAppend
+	// SQLReplace drops the table if it exists, and creates it again.
+	SQLReplace
+)
+
+// SQLPlaceholder formats the placeholder of the i-th argument of a statement, starting at 1.
+type SQLPlaceholder func(i int) string
+
+var (
+	// QuestionPlaceholder formats placeholders as ?, like SQLite and MySQL.
+	QuestionPlaceholder SQLPlaceholder = func(int) string { return "?" }
+	// DollarPlaceholder formats placeholders as $1, $2..., like Postgres.
+	DollarPlaceholder SQLPlaceholder = func(i int) string { return "$" + strconv.Itoa(i) }
+)
+
+// SQLQuote quotes a table or column name.
+type SQLQuote func(name string) string
+
+var (
+	// DoubleQuote quotes identifiers with double quotes, as in standard SQL, SQLite and Postgres.
+	DoubleQuote SQLQuote = func(name string) string { return `"` + strings.ReplaceAll(name, `"`, `""`) + `"` }
+	// BacktickQuote quotes identifiers with backticks, like MySQL.
+	BacktickQuote SQLQuote = func(name string) string { return "`" + strings.ReplaceAll(name, "`", "``") + "`" }
+)
+
+// SQLWriteOptions configures how WriteSQL writes a DataFrame.
+// The zero value creates the table, uses ? placeholders, and quotes identifiers with double quotes.
+type SQLWriteOptions struct {
+	Mode SQLWriteMode
+	// BatchSize is the number of rows inserted by each statement.
+	BatchSize int
+	// Placeholder formats the placeholders of statements. It defaults to QuestionPlaceholder.
+	Placeholder SQLPlaceholder
+	// Quote quotes the names of the table and its columns. It defaults to DoubleQuote.
+	Quote SQLQuote
+}
+
+// WriteSQL writes the rows of a DataFrame to a table, with multi-row INSERT statements.
+// All statements run in a single transaction, so the table is left unchanged if any of them fails.
+// Identifiers are quoted with SQLWriteOptions.Quote.
+func WriteSQL(ctx context.Context, db *sql.DB, table string, df *dataframe.DataFrame, opts SQLWriteOptions) error {
+	if err := df.Validate(); err != nil {
+		return err
+	}
+	if len(df.Series) == 0 {
+		return fmt.Errorf("cannot write a DataFrame without columns")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultSQLBatchSize
+	}
+	placeholder := opts.Placeholder
+	if placeholder == nil {
+		placeholder = QuestionPlaceholder
+	}
+	quoteIdentifier := opts.Quote
+	if quoteIdentifier == nil {
+		quoteIdentifier = DoubleQuote
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	switch opts.Mode {
+	case SQLCreate, SQLReplace:
+		if opts.Mode == SQLReplace {
+			if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+quoteIdentifier(table)); err != nil {
+				return err
+			}
+		}
+		defs := make([]string, len(df.Series))
+		for i, col := range df.Series {
+			sqlType, err := sqlTypeName(col.DataType())
+			if err != nil {
+				return fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			defs[i] = quoteIdentifier(col.Name) + " " + sqlType
+		}
+		stmt := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(table), strings.Join(defs, ", "))
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	case SQLAppend:
+	default:
+		return fmt.Errorf("unknown SQL write mode %d", opts.Mode)
+	}
+
+	names := make([]string, len(df.Series))
+	for i, col := range df.Series {
+		names[i] = quoteIdentifier(col.Name)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", quoteIdentifier(table), strings.Join(names, ", "))
+	for start := 0; start < df.Height(); start += batchSize {
+		end := start + batchSize
+		if end > df.Height() {
+			end = df.Height()
+		}
+		var sb strings.Builder
+		sb.WriteString(insert)
+		args := make([]any, 0, (end-start)*len(df.Series))
+		for row := start; row < end; row++ {
+			if row > start {
+				sb.WriteString(", ")
+			}
+			sb.WriteByte('(')
+			for j, col := range df.Series {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				args = append(args, nil)
+				sb.WriteString(placeholder(len(args)))
+				if v := col.ValueExn(row); v.Valid {
+					args[len(args)-1] = v.Value
+				}
+			}
+			sb.WriteByte(')')
+		}
+		if _, err := tx.ExecContext(ctx, sb.String(), args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// sqlTypeName returns the SQL type of columns of the given datatype.
+func sqlTypeName(dtype arrow.DataType) (string, error) {
+	switch dtype.ID() {
+	case arrow.INT64:
+		return "BIGINT", nil
+	case arrow.FLOAT64:
+		return "DOUBLE PRECISION", nil
+	case arrow.BOOL:
+		return "BOOLEAN", nil
+	case arrow.STRING:
+		return "TEXT", nil
+	case arrow.TIMESTAMP:
+		return "TIMESTAMP", nil
+	case arrow.DATE32:
+		return "DATE", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", dtype)
+}
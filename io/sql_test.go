@@ -0,0 +1,173 @@
+//go:build cgo
+
+package io_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/kstremick/mango/core/dataframe"
+	"github.com/kstremick/mango/core/primitive"
+	"github.com/kstremick/mango/core/series"
+	"github.com/kstremick/mango/io"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/zeebo/assert"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	// Every connection to :memory: opens a different database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	df := ipcTestDataFrame(t)
+	ts := time.Date(2023, 5, 1, 12, 30, 0, 0, time.UTC)
+	b := array.NewTimestampBuilder(memory.NewGoAllocator(), arrow.FixedWidthTypes.Timestamp_us.(*arrow.TimestampType))
+	defer b.Release()
+	b.AppendValues([]arrow.Timestamp{
+		arrow.Timestamp(ts.UnixMicro()), arrow.Timestamp(ts.Add(time.Hour).UnixMicro()), 0, arrow.Timestamp(ts.Add(2 * time.Hour).UnixMicro()),
+	}, []bool{true, true, false, true})
+	times := series.NewSeriesFromArray("at", b.NewArray())
+	flags := series.NewSeriesFromSlice("flag", []bool{true, false, true, false}, nil, false)
+	df = df.WithColumns(&times, &flags)
+
+	assert.NoError(t, io.WriteSQL(ctx, db, "scores", df, io.SQLWriteOptions{BatchSize: 3}))
+	got, err := io.ReadSQL(ctx, db, `SELECT * FROM scores ORDER BY id`)
+	assert.NoError(t, err)
+	assert.Equal(t, got.GetColumnNames(), []string{"id", "name", "score", "at", "flag"})
+	assert.Equal(t, got.Series[0].DataType(), arrow.PrimitiveTypes.Int64)
+	assert.Equal(t, got.Series[2].DataType(), arrow.PrimitiveTypes.Float64)
+	assert.Equal(t, got.Series[3].DataType(), arrow.FixedWidthTypes.Timestamp_us)
+	assert.Equal(t, got.Series[4].DataType(), arrow.FixedWidthTypes.Boolean)
+	for i := 0; i < df.Height(); i++ {
+		assert.Equal(t, got.RowSliceExn(i), df.RowSliceExn(i))
+	}
+}
+
+func TestWriteSQLModes(t *testing.T) {
+	type testCase struct {
+		name   string
+		mode   io.SQLWriteMode
+		height int
+		err    bool
+	}
+	testCases := []testCase{
+		{"create existing", io.SQLCreate, 0, true},
+		{"append", io.SQLAppend, 8, false},
+		{"replace", io.SQLReplace, 4, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := openTestDB(t)
+			df := ipcTestDataFrame(t)
+			assert.NoError(t, io.WriteSQL(ctx, db, "scores", df, io.SQLWriteOptions{}))
+			err := io.WriteSQL(ctx, db, "scores", df, io.SQLWriteOptions{Mode: tc.mode, Placeholder: io.DollarPlaceholder})
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			got, err := io.ReadSQL(ctx, db, `SELECT * FROM scores`)
+			assert.NoError(t, err)
+			assert.Equal(t, got.Height(), tc.height)
+		})
+	}
+}
+
+func TestReadSQL(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	_, err := db.Exec(`CREATE TABLE t (a INTEGER, b VARCHAR(10), c DECIMAL(10, 2), d DATE)`)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO t VALUES (1, 'x', 1.25, '2023-05-01'), (2, NULL, NULL, NULL)`)
+	assert.NoError(t, err)
+
+	type testCase struct {
+		name   string
+		query  string
+		args   []any
+		dtypes []arrow.DataType
+		cols   [][]interface{}
+	}
+	testCases := []testCase{
+		{
+			"declared types", `SELECT a, b, c FROM t ORDER BY a`, nil,
+			[]arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64},
+			[][]interface{}{{int64(1), int64(2)}, {"x", nil}, {1.25, nil}},
+		},
+		{
+			"expressions", `SELECT a * 2 AS d, b || '!' AS e FROM t WHERE a = ?`, []any{1},
+			[]arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String},
+			[][]interface{}{{int64(2)}, {"x!"}},
+		},
+		{
+			"dates", `SELECT d FROM t ORDER BY a`, nil,
+			[]arrow.DataType{arrow.FixedWidthTypes.Date32},
+			[][]interface{}{{time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), nil}},
+		},
+		{
+			"mixed numbers", `SELECT CASE WHEN a = 1 THEN 1 ELSE 2.5 END AS e FROM t ORDER BY a`, nil,
+			[]arrow.DataType{arrow.PrimitiveTypes.Float64},
+			[][]interface{}{{1.0, 2.5}},
+		},
+		{
+			"mixed types", `SELECT CASE WHEN a = 1 THEN 1 ELSE 'x' END AS e FROM t ORDER BY a`, nil,
+			[]arrow.DataType{arrow.BinaryTypes.String},
+			[][]interface{}{{"1", "x"}},
+		},
+		{
+			"empty", `SELECT a, b FROM t WHERE a > 10`, nil,
+			[]arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String},
+			[][]interface{}{{}, {}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := io.ReadSQL(ctx, db, tc.query, tc.args...)
+			assert.NoError(t, err)
+			for i, dtype := range tc.dtypes {
+				assert.Equal(t, got.Series[i].DataType(), dtype)
+				assert.Equal(t, columnValues(got.Series[i]), tc.cols[i])
+			}
+		})
+	}
+}
+
+func TestReadSQLInfersTypesAcrossBatches(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	// The values of v are all null until the last row, in the second batch of rows.
+	got, err := io.ReadSQL(ctx, db, `WITH RECURSIVE r(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM r WHERE i < 70000)
+		SELECT i, CASE WHEN i = 70000 THEN 1.5 END AS v FROM r`)
+	assert.NoError(t, err)
+	assert.Equal(t, 70000, got.Height())
+	v, err := got.Column("v")
+	assert.NoError(t, err)
+	assert.Equal(t, v.DataType(), arrow.PrimitiveTypes.Float64)
+	assert.Equal(t, v.NumChunks(), 2)
+	assert.Equal(t, v.ValueExn(69999), primitive.Some[interface{}](1.5))
+}
+
+func TestWriteSQLQuote(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	df := dataframe.NewDataFrame([]series.Series{series.NewSeries("a`b", []int64{1, 2})})
+	assert.NoError(t, io.WriteSQL(ctx, db, "t", df, io.SQLWriteOptions{Quote: io.BacktickQuote}))
+	got, err := io.ReadSQL(ctx, db, "SELECT `a``b` FROM `t`")
+	assert.NoError(t, err)
+	assert.Equal(t, got.GetColumnNames(), []string{"a`b"})
+	assert.Equal(t, got.Height(), 2)
+}